@@ -0,0 +1,27 @@
+package gogroup
+
+import "context"
+
+// Actor runs execute as a Go() task, in the style of github.com/oklog/run.
+// When the Group is canceled, interrupt is called with the Group error, or
+// the context.Cause() of the Group when there is none, and must make execute
+// return. Wait() waits for interrupt to return. Neither is called after the
+// Group has ended.
+//
+func (o *Group) Actor(execute func() error, interrupt func(error)) {
+	o.wait_lock.Lock()
+	ok := o.hold()
+	o.wait_lock.Unlock()
+	if !ok {
+		return
+	}
+	context.AfterFunc(o, func() {
+		defer o.wg().Done()
+		err := o.Get_err()
+		if err == nil {
+			err = context.Cause(o)
+		}
+		interrupt(err)
+	})
+	o.Go(execute)
+}
@@ -0,0 +1,129 @@
+// Copyright 2016 aletheia7. All rights reserved. Use of this source code is
+// governed by a BSD-2-Clause license that can be found in the LICENSE file.
+
+// Package agent serves gogroup.Group state over a local unix socket for
+// cmd/gogroupctl.
+//
+// A request is one line: a command and an optional group name. The response
+// is written and the connection closed.
+//
+//	list            name, active, registered, canceled of each group
+//	status <group>  state, active, registered, interrupted, error
+//	tasks <group>   running tasks: index, name, duration, caller
+//	stats <group>   gogroup.Stats as JSON
+//	dump <group>    Group.Dump() tree
+//	cancel <group>  Group.Cancel()
+//	quiesce <group> Group.Drain()
+//
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/aletheia7/gogroup"
+)
+
+// Socket returns the default socket path for process pid.
+//
+func Socket(pid int) string {
+	return filepath.Join(os.TempDir(), fmt.Sprintf("gogroup.%v.sock", pid))
+}
+
+type Agent struct {
+	l      net.Listener
+	path   string
+	lock   sync.Mutex
+	groups map[string]*gogroup.Group
+}
+
+// Listen serves on the unix socket path with gogroup.Listen_control(). If
+// path is "", Socket(os.Getpid()) is used.
+//
+func Listen(path string) (*Agent, error) {
+	if path == "" {
+		path = Socket(os.Getpid())
+	}
+	l, err := gogroup.Listen_control(path)
+	if err != nil {
+		return nil, err
+	}
+	r := &Agent{l: l, path: path, groups: map[string]*gogroup.Group{}}
+	go r.serve()
+	return r, nil
+}
+
+// Add makes g available under name. A group with the same name is replaced.
+//
+func (o *Agent) Add(name string, g *gogroup.Group) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	o.groups[name] = g
+}
+
+func (o *Agent) Remove(name string) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	delete(o.groups, name)
+}
+
+// Close stops serving and removes the socket file.
+//
+func (o *Agent) Close() error {
+	return o.l.Close()
+}
+
+func (o *Agent) serve() {
+	for {
+		c, err := o.l.Accept()
+		if err != nil {
+			return
+		}
+		go gogroup.Control_conn(c, o.exec)
+	}
+}
+
+// exec runs list for all groups, and the other commands with
+// gogroup.Group.Control() of the named group.
+//
+func (o *Agent) exec(w io.Writer, args []string) error {
+	if len(args) == 0 {
+		return errors.New("no command")
+	}
+	if args[0] == "list" {
+		o.lock.Lock()
+		names := make([]string, 0, len(o.groups))
+		for name := range o.groups {
+			names = append(names, name)
+		}
+		o.lock.Unlock()
+		sort.Strings(names)
+		for _, name := range names {
+			if g := o.group(name); g != nil {
+				s := g.Stats()
+				fmt.Fprintf(w, "%v\t%v\t%v\t%v\n", name, s.Active, s.Registered, s.Canceled)
+			}
+		}
+		return nil
+	}
+	if len(args) != 2 {
+		return fmt.Errorf("usage: %v <group>", args[0])
+	}
+	g := o.group(args[1])
+	if g == nil {
+		return fmt.Errorf("group not found: %v", args[1])
+	}
+	return g.Control(w, args[:1])
+}
+
+func (o *Agent) group(name string) *gogroup.Group {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	return o.groups[name]
+}
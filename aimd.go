@@ -0,0 +1,73 @@
+package gogroup
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+type aimd struct {
+	min, max int
+	target   time.Duration
+	credit   float64
+}
+
+// With_adaptive_limit() is With_limit() with a limit adjusted to task
+// feedback, for a downstream dependency whose right limit is unknown. The
+// limit starts at min. A task that returns an error, other than
+// context.Canceled, panics, or runs longer than target lowers the limit by
+// a tenth, at least by one (multiplicative decrease); each limit tasks that
+// end in time raise it by one (additive increase), up to max. Tasks beyond
+// the limit are queued; see With_queue(), whose size is extended by
+// max - limit.
+//
+func With_adaptive_limit(min, max int, target time.Duration) option {
+	if min < 1 {
+		min = 1
+	}
+	if max < min {
+		max = min
+	}
+	return func(o *Group) {
+		a := o.admission()
+		a.limit = min
+		a.aimd = &aimd{min: min, max: max, target: target}
+	}
+}
+
+// Limit returns the With_limit(), SetLimit() or With_adaptive_limit()
+// limit, -1 when unlimited.
+//
+func (o *Group) Limit() int {
+	a := o.admit
+	if a == nil {
+		return -1
+	}
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	return a.limit
+}
+
+// feedback adjusts the limit after a task ran for d. o.lock must be held.
+//
+func (o *admission) feedback(d time.Duration, ok bool, err error) {
+	m := o.aimd
+	if !ok || err != nil && !errors.Is(err, context.Canceled) || m.target < d {
+		limit := int(float64(o.limit) * 0.9)
+		if limit == o.limit {
+			limit--
+		}
+		if limit < m.min {
+			limit = m.min
+		}
+		o.limit, m.credit = limit, 0
+		return
+	}
+	m.credit += 1 / float64(o.limit)
+	if 1 <= m.credit {
+		m.credit = 0
+		if o.limit < m.max {
+			o.limit++
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package gogroup
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var Err_batcher_closed = errors.New("gogroup: batcher closed")
+
+// Batcher collects values from Add() and calls flush with a batch when size
+// values are collected or the oldest value is max_age old. When the Group is
+// canceled the remaining values are flushed with a context that is not
+// canceled, before Wait() returns. A flush error is passed to Set_err() and
+// cancels the Group.
+//
+type Batcher[T any] struct {
+	g      *Group
+	in     chan T
+	lock   sync.RWMutex
+	closed bool
+}
+
+// New_batcher starts the Batcher task in g.
+//
+func New_batcher[T any](g *Group, size int, max_age time.Duration, flush func(ctx context.Context, batch []T) error) *Batcher[T] {
+	if size < 1 {
+		size = 1
+	}
+	r := &Batcher[T]{g: g, in: make(chan T)}
+	err := g.spawn("batcher", false, func() error {
+		batch := make([]T, 0, size)
+		t := time.NewTimer(max_age)
+		t.Stop()
+		defer t.Stop()
+		send := func(ctx context.Context) error {
+			// Drain a fire not yet received, so Reset() does not see it.
+			if !t.Stop() {
+				select {
+				case <-t.C:
+				default:
+				}
+			}
+			if len(batch) == 0 {
+				return nil
+			}
+			err := flush(ctx, batch)
+			batch = make([]T, 0, size)
+			return err
+		}
+		for {
+			select {
+			case v := <-r.in:
+				if len(batch) == 0 {
+					t.Reset(max_age)
+				}
+				if batch = append(batch, v); size <= len(batch) {
+					if err := send(g); err != nil {
+						return err
+					}
+				}
+			case <-t.C:
+				if err := send(g); err != nil {
+					return err
+				}
+			case <-g.Done():
+				r.lock.Lock()
+				r.closed = true
+				r.lock.Unlock()
+				return send(context.WithoutCancel(g))
+			}
+		}
+	})
+	if err != nil {
+		r.closed = true
+	}
+	return r
+}
+
+// Add adds v to the current batch. Returns Err_batcher_closed after the Group
+// is canceled.
+//
+func (o *Batcher[T]) Add(v T) error {
+	o.lock.RLock()
+	defer o.lock.RUnlock()
+	if o.closed {
+		return Err_batcher_closed
+	}
+	select {
+	case o.in <- v:
+		return nil
+	case <-o.g.Done():
+		return Err_batcher_closed
+	}
+}
@@ -0,0 +1,165 @@
+package gogroup
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Err_breaker_open is reported for tasks rejected by an open Breaker.
+//
+var Err_breaker_open = errors.New("gogroup: circuit breaker open")
+
+type Breaker_state int
+
+const (
+	Breaker_closed    Breaker_state = iota // tasks run
+	Breaker_open                           // tasks fail fast
+	Breaker_half_open                      // one probe task runs
+)
+
+// Breaker_policy configures a Breaker. Zero values use the defaults.
+//
+type Breaker_policy struct {
+	Window    int                          // last results used for the failure rate; default 20
+	Rate      float64                      // failure rate opening the Breaker; default 0.5
+	Cool_down time.Duration                // open time before a half-open probe; default 10s
+	On_error  func(name string, err error) // task errors and Err_breaker_open
+}
+
+// Breaker is a circuit breaker scoped to a Group. Tasks started with
+// Breaker.Go() are not run once the failure rate of the last Window tasks
+// reaches Rate. After Cool_down one probe task is run; success closes the
+// Breaker, failure opens it again. Breaker task errors are passed to
+// On_error instead of Set_err() and do not cancel the Group.
+//
+type Breaker struct {
+	g       *Group
+	name    string
+	policy  Breaker_policy
+	lock    sync.Mutex
+	state   Breaker_state
+	results []bool // true is a failure
+	opened  time.Time
+}
+
+// Breaker returns the Breaker named name, making it with p on first use.
+//
+func (o *Group) Breaker(name string, p Breaker_policy) *Breaker {
+	o.wait_lock.Lock()
+	defer o.wait_lock.Unlock()
+	if b, ok := o.breakers[name]; ok {
+		return b
+	}
+	if p.Window <= 0 {
+		p.Window = 20
+	}
+	if p.Rate <= 0 {
+		p.Rate = 0.5
+	}
+	if p.Cool_down <= 0 {
+		p.Cool_down = 10 * time.Second
+	}
+	if o.breakers == nil {
+		o.breakers = map[string]*Breaker{}
+	}
+	b := &Breaker{g: o, name: name, policy: p}
+	o.breakers[name] = b
+	return b
+}
+
+func (o *Breaker) State() Breaker_state {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	return o.state
+}
+
+// Go runs f as a task named after the Breaker, or reports Err_breaker_open to
+// On_error without running f. A panic in f counts as a failure, then goes
+// on to With_recover().
+//
+func (o *Breaker) Go(f func(ctx context.Context) error) {
+	if !o.allow() {
+		o.report(Err_breaker_open)
+		return
+	}
+	if err := o.g.spawn(o.name, false, func() error {
+		failed := true // a panic in f is a failure
+		defer func() { o.record(failed) }()
+		err := f(o.g)
+		failed = err != nil
+		o.report(err)
+		return nil
+	}); err != nil {
+		o.unprobe()
+		o.report(err)
+	}
+}
+
+// unprobe returns a half-open Breaker to open when its probe did not run, so
+// the next task is the probe.
+//
+func (o *Breaker) unprobe() {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	if o.state == Breaker_half_open {
+		o.state = Breaker_open
+	}
+}
+
+func (o *Breaker) allow() bool {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	switch o.state {
+	case Breaker_open:
+		if time.Since(o.opened) < o.policy.Cool_down {
+			return false
+		}
+		o.state = Breaker_half_open
+		return true
+	case Breaker_half_open:
+		return false
+	}
+	return true
+}
+
+func (o *Breaker) record(failed bool) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	if o.state == Breaker_half_open {
+		o.results = o.results[:0]
+		if failed {
+			o.state, o.opened = Breaker_open, time.Now()
+		} else {
+			o.state = Breaker_closed
+		}
+		return
+	}
+	if o.state != Breaker_closed {
+		return
+	}
+	o.results = append(o.results, failed)
+	if n := len(o.results) - o.policy.Window; 0 < n {
+		o.results = append(o.results[:0], o.results[n:]...)
+	}
+	if len(o.results) < o.policy.Window {
+		return
+	}
+	failures := 0
+	for _, f := range o.results {
+		if f {
+			failures++
+		}
+	}
+	if o.policy.Rate <= float64(failures)/float64(len(o.results)) {
+		o.state, o.opened = Breaker_open, time.Now()
+		o.results = o.results[:0]
+	}
+}
+
+func (o *Breaker) report(err error) {
+	if err != nil && o.policy.On_error != nil {
+		o.policy.On_error(o.name, err)
+	}
+}
@@ -0,0 +1,32 @@
+package gogroup
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func wait_state(t *testing.T, b *Breaker, want Breaker_state) {
+	t.Helper()
+	for end := time.Now().Add(5 * time.Second); b.State() != want; {
+		if end.Before(time.Now()) {
+			t.Fatalf("State() = %v, want %v", b.State(), want)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestBreaker_panic(t *testing.T) {
+	g := recovering()
+	b := g.Breaker("b", Breaker_policy{Window: 1, Cool_down: time.Millisecond})
+	b.Go(func(ctx context.Context) error { panic("closed") })
+	wait_state(t, b, Breaker_open)
+	time.Sleep(2 * time.Millisecond)
+	b.Go(func(ctx context.Context) error { panic("probe") })
+	wait_state(t, b, Breaker_open)
+	time.Sleep(2 * time.Millisecond)
+	b.Go(func(ctx context.Context) error { return nil })
+	wait_state(t, b, Breaker_closed)
+	g.Cancel()
+	g.Wait()
+}
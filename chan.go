@@ -0,0 +1,85 @@
+package gogroup
+
+import "sync"
+
+// Merge sends the values from chans on the returned channel. The channel is
+// closed when all chans are closed or g is canceled. Each input is forwarded
+// by a task of g that does not cancel g when it ends.
+//
+func Merge[T any](g *Group, chans ...<-chan T) <-chan T {
+	out := make(chan T)
+	var wg sync.WaitGroup
+	wg.Add(len(chans))
+	for _, c := range chans {
+		c := c
+		err := g.spawn("merge", false, func() error {
+			defer wg.Done()
+			for {
+				select {
+				case <-g.Done():
+					return nil
+				case v, ok := <-c:
+					if !ok {
+						return nil
+					}
+					select {
+					case out <- v:
+					case <-g.Done():
+						return nil
+					}
+				}
+			}
+		})
+		if err != nil {
+			wg.Done()
+		}
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+// Tee sends every value from in on each of n returned channels. A value is
+// sent on all channels before the next value is received, so the slowest
+// consumer sets the pace. The channels are closed when in is closed or g is
+// canceled. The copying is a task of g that does not cancel g when it ends.
+//
+func Tee[T any](g *Group, in <-chan T, n int) []<-chan T {
+	outs := make([]chan T, n)
+	r := make([]<-chan T, n)
+	for i := range outs {
+		outs[i] = make(chan T)
+		r[i] = outs[i]
+	}
+	close_outs := func() {
+		for _, out := range outs {
+			close(out)
+		}
+	}
+	err := g.spawn("tee", false, func() error {
+		defer close_outs()
+		for {
+			select {
+			case <-g.Done():
+				return nil
+			case v, ok := <-in:
+				if !ok {
+					return nil
+				}
+				for _, out := range outs {
+					select {
+					case out <- v:
+					case <-g.Done():
+						return nil
+					}
+				}
+			}
+		}
+	})
+	if err != nil {
+		close_outs()
+	}
+	return r
+}
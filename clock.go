@@ -0,0 +1,229 @@
+package gogroup
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock is the time source of a Group. *time.Timer is a Timer.
+//
+type Clock interface {
+	Now() time.Time
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// Timer is returned by Clock.AfterFunc().
+//
+type Timer interface {
+	Stop() bool
+	Reset(d time.Duration) bool
+}
+
+// With_clock() sets the Clock used by With_timeout(), With_deadline(), the
+// _nowait variants, With_sliding_timeout(), With_idle_timeout(),
+// With_max_lifetime(), Go_tick(), Go_after(), Scheduler jobs, and the
+// backoff of Go_retry() and supervisors. Use a Fake_clock to test timeouts
+// without real sleeps.
+// Child Groups use the Clock of their parent unless they set one.
+//
+// With a Clock, a deadline cancels the Group with Err() and context.Cause()
+// context.DeadlineExceeded, as with the real clock. A deadline that is not
+// after Clock.Now() cancels the Group in New().
+//
+func With_clock(c Clock) option {
+	return func(o *Group) {
+		o.clock = c
+	}
+}
+
+func (o *Group) now() time.Time {
+	if o.clock == nil {
+		return time.Now()
+	}
+	return o.clock.Now()
+}
+
+func (o *Group) after_func(d time.Duration, f func()) Timer {
+	if o.clock == nil {
+		return time.AfterFunc(d, f)
+	}
+	return o.clock.AfterFunc(d, f)
+}
+
+// with_deadline makes the deadline Context of the timeout options using the
+// Group Clock.
+//
+func (o *Group) with_deadline() {
+	t := o.expires(o.now())
+	o.expires = nil
+	if o.clock == nil {
+		o.Context, o.CancelFunc = context.WithDeadline(o.Context, t)
+		return
+	}
+	c := &clock_deadline{Context: o.Context, t: t}
+	c.done, c.cancel = context.WithCancel(context.Background())
+	parent := o.Context
+	unlink := context.AfterFunc(parent, func() { c.stop(parent.Err()) })
+	var timer Timer
+	if d := t.Sub(o.clock.Now()); d <= 0 {
+		c.stop(context.DeadlineExceeded)
+	} else {
+		timer = o.clock.AfterFunc(d, func() { c.stop(context.DeadlineExceeded) })
+	}
+	o.Context = c
+	o.CancelFunc = func() {
+		if timer != nil {
+			timer.Stop()
+		}
+		unlink()
+		c.stop(context.Canceled)
+	}
+}
+
+// clock_deadline is a Context canceled by a Clock deadline with Err()
+// context.DeadlineExceeded, as context.WithDeadline() does with the real
+// clock. The embedded Context is the parent, for Value().
+//
+type clock_deadline struct {
+	context.Context
+	t      time.Time
+	done   context.Context
+	cancel context.CancelFunc
+	lock   sync.Mutex
+	err    error
+}
+
+func (o *clock_deadline) Deadline() (time.Time, bool) { return o.t, true }
+func (o *clock_deadline) Done() <-chan struct{}       { return o.done.Done() }
+
+func (o *clock_deadline) Err() error {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	return o.err
+}
+
+// AfterFunc lets contexts derived from o be canceled without a goroutine.
+//
+func (o *clock_deadline) AfterFunc(f func()) func() bool {
+	return context.AfterFunc(o.done, f)
+}
+
+func (o *clock_deadline) stop(err error) {
+	o.lock.Lock()
+	if o.err == nil {
+		o.err = err
+	}
+	o.lock.Unlock()
+	o.cancel()
+}
+
+// Fake_clock is a Clock that only moves with Advance(). It is safe for
+// concurrent use.
+//
+type Fake_clock struct {
+	lock   sync.Mutex
+	now    time.Time
+	timers []*fake_timer
+}
+
+// New_fake_clock returns a Fake_clock set to now.
+//
+func New_fake_clock(now time.Time) *Fake_clock {
+	return &Fake_clock{now: now}
+}
+
+func (o *Fake_clock) Now() time.Time {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	return o.now
+}
+
+func (o *Fake_clock) AfterFunc(d time.Duration, f func()) Timer {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	t := &fake_timer{clock: o, f: f}
+	t.reset(d)
+	return t
+}
+
+// Waiters returns the number of pending timers, so a test can wait for a
+// task to sleep before calling Advance().
+//
+func (o *Fake_clock) Waiters() int {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	return len(o.timers)
+}
+
+// Advance moves the clock d later and calls, in time order, the func of each
+// timer that is due. The funcs are called by Advance(), not in a goroutine.
+//
+func (o *Fake_clock) Advance(d time.Duration) {
+	o.lock.Lock()
+	end := o.now.Add(d)
+	for {
+		if len(o.timers) == 0 || end.Before(o.timers[0].when) {
+			break
+		}
+		t := o.timers[0]
+		o.timers = o.timers[1:]
+		t.active = false
+		if o.now.Before(t.when) {
+			o.now = t.when
+		}
+		o.lock.Unlock()
+		t.f()
+		o.lock.Lock()
+	}
+	o.now = end
+	o.lock.Unlock()
+}
+
+type fake_timer struct {
+	clock  *Fake_clock
+	f      func()
+	when   time.Time
+	active bool
+}
+
+// stop removes the timer. clock.lock must be held.
+//
+func (o *fake_timer) stop() bool {
+	if !o.active {
+		return false
+	}
+	o.active = false
+	for i, t := range o.clock.timers {
+		if t == o {
+			o.clock.timers = append(o.clock.timers[:i], o.clock.timers[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+// reset schedules the timer d from now. clock.lock must be held.
+//
+func (o *fake_timer) reset(d time.Duration) bool {
+	active := o.stop()
+	o.when, o.active = o.clock.now.Add(d), true
+	o.clock.timers = append(o.clock.timers, o)
+	sort.SliceStable(o.clock.timers, func(i, j int) bool {
+		return o.clock.timers[i].when.Before(o.clock.timers[j].when)
+	})
+	return active
+}
+
+func (o *fake_timer) Stop() bool {
+	o.clock.lock.Lock()
+	defer o.clock.lock.Unlock()
+	return o.stop()
+}
+
+func (o *fake_timer) Reset(d time.Duration) bool {
+	o.clock.lock.Lock()
+	defer o.clock.lock.Unlock()
+	return o.reset(d)
+}
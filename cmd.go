@@ -0,0 +1,62 @@
+package gogroup
+
+import (
+	"os/exec"
+	"time"
+)
+
+type cmd_option func(o *commander)
+
+type commander struct {
+	kill_after time.Duration
+}
+
+// With_kill_after() sets how long Go_cmd() waits after SIGTERM before
+// SIGKILL. Default is 10s.
+//
+func With_kill_after(d time.Duration) cmd_option {
+	return func(o *commander) {
+		o.kill_after = d
+	}
+}
+
+// Go_cmd starts cmd in its own process group as a Go() task and waits for it
+// to exit. The exit error is passed to Set_err(). When the Group is
+// canceled, SIGTERM is sent to the process group, then SIGKILL after
+// With_kill_after(). An exit caused by those signals is not an error.
+//
+func (o *Group) Go_cmd(cmd *exec.Cmd, opt ...cmd_option) {
+	c := &commander{kill_after: 10 * time.Second}
+	for _, f := range opt {
+		f(c)
+	}
+	o.Go_name("cmd "+cmd.Path, func() error {
+		set_pgid(cmd)
+		if err := cmd.Start(); err != nil {
+			return err
+		}
+		done := make(chan error, 1)
+		go func() {
+			done <- cmd.Wait()
+		}()
+		var err error
+		select {
+		case err = <-done:
+			return err
+		case <-o.Done():
+		}
+		cmd_terminate(cmd)
+		t := time.NewTimer(c.kill_after)
+		defer t.Stop()
+		select {
+		case err = <-done:
+		case <-t.C:
+			cmd_kill(cmd)
+			err = <-done
+		}
+		if cmd_signaled(err) {
+			return nil
+		}
+		return err
+	})
+}
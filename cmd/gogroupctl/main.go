@@ -0,0 +1,52 @@
+// Copyright 2016 aletheia7. All rights reserved. Use of this source code is
+// governed by a BSD-2-Clause license that can be found in the LICENSE file.
+
+// gogroupctl sends a command to a process serving gogroup/agent, or to a
+// Group served with gogroup.With_control_socket().
+//
+//	gogroupctl -p <pid> list
+//	gogroupctl -p <pid> tasks|stats|dump|cancel|quiesce <group>
+//	gogroupctl -s <socket> status|tasks|stats|dump|cancel|quiesce
+//
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/aletheia7/gogroup/agent"
+)
+
+func main() {
+	pid := flag.Int("p", 0, "process id")
+	socket := flag.String("s", "", "socket path; overrides -p")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %v [-p pid | -s socket] list | status | tasks|stats|dump|cancel|quiesce [group]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 || (*pid == 0 && *socket == "") {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *socket == "" {
+		*socket = agent.Socket(*pid)
+	}
+	c, err := net.Dial("unix", *socket)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	defer c.Close()
+	if _, err = fmt.Fprintln(c, strings.Join(flag.Args(), " ")); err == nil {
+		_, err = io.Copy(os.Stdout, c)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,19 @@
+//go:build !unix
+
+package gogroup
+
+import "os/exec"
+
+func set_pgid(cmd *exec.Cmd) {}
+
+func cmd_terminate(cmd *exec.Cmd) {
+	cmd.Process.Kill()
+}
+
+func cmd_kill(cmd *exec.Cmd) {
+	cmd.Process.Kill()
+}
+
+func cmd_signaled(err error) bool {
+	return false
+}
@@ -0,0 +1,165 @@
+package gogroup
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// With_control_socket() serves the Group on the unix socket path, so
+// operational tooling, such as cmd/gogroupctl -s path, can query or cancel
+// it. The protocol is the one of gogroup/agent: one command line, then the
+// response. The group name argument is optional.
+//
+//	status          state, active, registered, interrupted, error
+//	tasks           running tasks: index, name, duration, caller
+//	stats           Stats as JSON
+//	dump            Dump() tree
+//	cancel          Cancel(); Wait() still waits for the tasks
+//	quiesce         Drain()
+//
+// The socket is only for the user: its mode is 0600. A socket file at path
+// that refuses connections is removed; one that is served is an error. The
+// socket and its connections are closed before Wait() returns. The Group is
+// canceled with the error when path cannot be served.
+//
+func With_control_socket(path string) option {
+	return func(o *Group) {
+		o.control = path
+	}
+}
+
+func (o *Group) start_control() {
+	if o.control == "" {
+		return
+	}
+	l, err := Listen_control(o.control)
+	if err != nil {
+		o.Cancel_err(err)
+		return
+	}
+	ln := &listener{conns: map[net.Conn]struct{}{}}
+	var wg sync.WaitGroup
+	o.Defer(func() {
+		l.Close()
+		ln.close()
+		wg.Wait()
+	})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			c, err := l.Accept()
+			if err != nil {
+				return
+			}
+			ln.add(c)
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer ln.remove(c)
+				Control_conn(c, o.Control)
+			}()
+		}
+	}()
+}
+
+// Listen_control listens on the unix socket path for the protocol of
+// With_control_socket(), as gogroup/agent does. The socket is only for the
+// user: its mode is 0600. A socket file at path that refuses connections is
+// removed; one that is served is an error.
+//
+func Listen_control(path string) (net.Listener, error) {
+	if err := remove_stale(path); err != nil {
+		return nil, err
+	}
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		l.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+// remove_stale removes the socket at path when nothing accepts on it, e.g.
+// after a crash. It is an error when path is served or is not a socket.
+//
+func remove_stale(path string) error {
+	fi, err := os.Lstat(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return nil
+	case err != nil:
+		return err
+	case fi.Mode()&os.ModeSocket == 0:
+		return fmt.Errorf("gogroup: %v is not a socket", path)
+	}
+	if c, err := net.DialTimeout("unix", path, time.Second); err == nil {
+		c.Close()
+		return fmt.Errorf("gogroup: %v is in use", path)
+	}
+	return os.Remove(path)
+}
+
+// Control_conn reads one command line from c, calls exec with its fields and
+// writes the response, or the error, to c, then closes c.
+//
+func Control_conn(c net.Conn, exec func(w io.Writer, args []string) error) {
+	defer c.Close()
+	c.SetDeadline(time.Now().Add(10 * time.Second))
+	line, err := bufio.NewReader(c).ReadString('\n')
+	if err != nil && line == "" {
+		return
+	}
+	w := bufio.NewWriter(c)
+	defer w.Flush()
+	if err := exec(w, strings.Fields(line)); err != nil {
+		fmt.Fprintf(w, "error: %v\n", err)
+	}
+}
+
+// Control runs the With_control_socket() command args for the Group and
+// writes the response to w.
+//
+func (o *Group) Control(w io.Writer, args []string) error {
+	switch {
+	case len(args) == 0:
+		return errors.New("no command")
+	case 2 < len(args):
+		return fmt.Errorf("usage: %v [group]", args[0])
+	case len(args) == 2 && args[1] != o.name:
+		return fmt.Errorf("group not found: %v", args[1])
+	}
+	switch args[0] {
+	case "status", "list":
+		s := o.Stats()
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%v\n", o.name, o.State(), s.Active, s.Registered, s.Interrupted, s.Err)
+	case "tasks":
+		for _, t := range o.Tasks() {
+			fmt.Fprintf(w, "%v\t%v\t%v\t%v\n", t.Index, t.Name, t.Duration().Round(time.Millisecond), t.Caller())
+		}
+	case "stats":
+		return json.NewEncoder(w).Encode(o.Stats())
+	case "dump":
+		o.Dump(w)
+	case "cancel":
+		o.Cancel()
+		fmt.Fprintln(w, "ok")
+	case "quiesce":
+		o.Drain()
+		fmt.Fprintln(w, "ok")
+	default:
+		return fmt.Errorf("unknown command: %v", args[0])
+	}
+	return nil
+}
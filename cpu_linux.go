@@ -0,0 +1,16 @@
+package gogroup
+
+import (
+	"syscall"
+	"time"
+)
+
+// thread_cpu returns the user + system CPU time of the calling OS thread.
+//
+func thread_cpu() (time.Duration, bool) {
+	var ru syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_THREAD, &ru); err != nil {
+		return 0, false
+	}
+	return time.Duration(ru.Utime.Nano() + ru.Stime.Nano()), true
+}
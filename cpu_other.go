@@ -0,0 +1,9 @@
+//go:build !linux
+
+package gogroup
+
+import "time"
+
+func thread_cpu() (time.Duration, bool) {
+	return 0, false
+}
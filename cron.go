@@ -0,0 +1,216 @@
+package gogroup
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Schedule returns the next activation time after t. A zero time ends the
+// schedule.
+//
+type Schedule interface {
+	Next(t time.Time) time.Time
+}
+
+// Every returns a Schedule activating every d.
+//
+func Every(d time.Duration) Schedule {
+	return every(d)
+}
+
+type every time.Duration
+
+func (o every) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(o))
+}
+
+type cron struct {
+	minute, hour, dom, month, dow uint64
+	dom_star, dow_star            bool
+}
+
+var cron_macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Parse_cron parses a 5 field cron expression: minute hour day-of-month month
+// day-of-week. Fields accept *, lists (1,2), ranges (1-5), and steps (*/15,
+// 1-30/5). Day-of-week is 0-7 with 0 and 7 Sunday. The @yearly, @monthly,
+// @weekly, @daily, and @hourly macros are accepted. When both day fields are
+// restricted, either may match.
+//
+func Parse_cron(spec string) (Schedule, error) {
+	if m, ok := cron_macros[spec]; ok {
+		spec = m
+	}
+	f := strings.Fields(spec)
+	if len(f) != 5 {
+		return nil, fmt.Errorf("gogroup: cron %q: want 5 fields", spec)
+	}
+	var (
+		r   cron
+		err error
+	)
+	for i, x := range []struct {
+		bits     *uint64
+		min, max int
+	}{{&r.minute, 0, 59}, {&r.hour, 0, 23}, {&r.dom, 1, 31}, {&r.month, 1, 12}, {&r.dow, 0, 7}} {
+		if *x.bits, err = parse_cron_field(f[i], x.min, x.max); err != nil {
+			return nil, fmt.Errorf("gogroup: cron %q: %w", spec, err)
+		}
+	}
+	if r.dow&(1<<7) != 0 {
+		r.dow |= 1
+	}
+	r.dom_star, r.dow_star = f[2] == "*", f[4] == "*"
+	return &r, nil
+}
+
+func parse_cron_field(field string, min, max int) (r uint64, err error) {
+	for _, part := range strings.Split(field, ",") {
+		lo, hi, step := min, max, 1
+		if i := strings.IndexByte(part, '/'); 0 <= i {
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step < 1 {
+				return 0, fmt.Errorf("bad step %q", part)
+			}
+			part = part[:i]
+		}
+		switch i := strings.IndexByte(part, '-'); {
+		case part == "*":
+		case 0 <= i:
+			if lo, err = strconv.Atoi(part[:i]); err == nil {
+				hi, err = strconv.Atoi(part[i+1:])
+			}
+		default:
+			if lo, err = strconv.Atoi(part); err == nil && step == 1 {
+				hi = lo
+			}
+		}
+		if err != nil || lo < min || max < hi || hi < lo {
+			return 0, fmt.Errorf("bad field %q", field)
+		}
+		for v := lo; v <= hi; v += step {
+			r |= 1 << uint(v)
+		}
+	}
+	return
+}
+
+func (o *cron) day(t time.Time) bool {
+	dom, dow := o.dom&(1<<uint(t.Day())) != 0, o.dow&(1<<uint(t.Weekday())) != 0
+	if o.dom_star || o.dow_star {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+func (o *cron) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute()+1, 0, 0, loc)
+	for end := t.AddDate(5, 0, 0); t.Before(end); {
+		y, m, d := t.Date()
+		switch {
+		case o.month&(1<<uint(m)) == 0:
+			t = time.Date(y, m+1, 1, 0, 0, 0, 0, loc)
+		case !o.day(t):
+			t = time.Date(y, m, d+1, 0, 0, 0, 0, loc)
+		case o.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(y, m, d, t.Hour()+1, 0, 0, 0, loc)
+		case o.minute&(1<<uint(t.Minute())) == 0:
+			t = time.Date(y, m, d, t.Hour(), t.Minute()+1, 0, 0, loc)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// Missed_run is reported by a Scheduler for an activation that did not run.
+//
+type Missed_run struct {
+	Job     string
+	At      time.Time // scheduled activation
+	Overlap bool      // the previous run was still running; otherwise the Scheduler was late
+}
+
+// Scheduler runs jobs on a Schedule as Group tasks until the Group is
+// canceled. A run that ends without an error does not cancel the Group; an
+// error is passed to Set_err() and cancels the Group.
+//
+type Scheduler struct {
+	g      *Group
+	report func(Missed_run)
+}
+
+// New_scheduler returns a Scheduler bound to g. report, if not nil, is called
+// for each activation that was skipped.
+//
+func New_scheduler(g *Group, report func(Missed_run)) *Scheduler {
+	return &Scheduler{g: g, report: report}
+}
+
+// Add_cron is Add() with Parse_cron(spec).
+//
+func (o *Scheduler) Add_cron(name, spec string, f func(ctx context.Context) error) error {
+	s, err := Parse_cron(spec)
+	if err != nil {
+		return err
+	}
+	o.Add(name, s, f)
+	return nil
+}
+
+// Add runs f at each activation of s. An activation is skipped when the
+// previous run of f has not ended.
+//
+func (o *Scheduler) Add(name string, s Schedule, f func(ctx context.Context) error) {
+	var running sync.Mutex
+	o.g.spawn(name, false, func() error {
+		last := o.g.now()
+		for {
+			next := s.Next(last)
+			if next.IsZero() {
+				return nil
+			}
+			if !sleep(o.g, next.Sub(o.g.now())) {
+				return nil
+			}
+			now := o.g.now()
+			for n := s.Next(next); !n.IsZero() && !n.After(now); n = s.Next(n) {
+				o.missed(Missed_run{Job: name, At: next})
+				next = n
+			}
+			last = next
+			if !running.TryLock() {
+				o.missed(Missed_run{Job: name, At: next, Overlap: true})
+				continue
+			}
+			if err := o.g.spawn(name, false, func() error {
+				defer running.Unlock()
+				return f(o.g)
+			}); err != nil {
+				running.Unlock()
+				return nil
+			}
+		}
+	})
+}
+
+func (o *Scheduler) missed(m Missed_run) {
+	if o.g.log != nil {
+		o.g.log.Warn("missed run", "job", m.Job, "at", m.At, "overlap", m.Overlap)
+	}
+	if o.report != nil {
+		o.report(m)
+	}
+}
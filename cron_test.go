@@ -0,0 +1,33 @@
+package gogroup
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestScheduler_clock(t *testing.T) {
+	c := New_fake_clock(time.Date(2024, 1, 1, 2, 59, 0, 0, time.Local))
+	g := New(With_cancel_nowait(context.Background()), With_clock(c))
+	ran := make(chan time.Time, 1)
+	if err := New_scheduler(g, nil).Add_cron("job", "0 3 * * *", func(ctx context.Context) error {
+		ran <- c.Now()
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	for c.Waiters() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	c.Advance(time.Minute)
+	select {
+	case at := <-ran:
+		if want := time.Date(2024, 1, 1, 3, 0, 0, 0, time.Local); !at.Equal(want) {
+			t.Fatalf("job ran at %v, want %v", at, want)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("job did not run after Advance()")
+	}
+	g.Cancel()
+	g.Wait()
+}
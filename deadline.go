@@ -0,0 +1,65 @@
+package gogroup
+
+import "time"
+
+// With_sliding_timeout() cancels the Group when no task has ended for d.
+// Each task end, and Extend_deadline(), pushes the deadline out, so a Group
+// of batch work is canceled only when progress stalls.
+//
+func With_sliding_timeout(d time.Duration) option {
+	return func(o *Group) {
+		o.slide = d
+	}
+}
+
+// Extend_deadline moves the extendable deadline to d from now, unless it is
+// already later, so repeated calls do not add up. The Group is canceled when
+// the deadline passes. The Context deadline of With_timeout() and
+// With_deadline() is not changed and still applies.
+//
+func (o *Group) Extend_deadline(d time.Duration) {
+	o.wait_lock.Lock()
+	defer o.wait_lock.Unlock()
+	if t := o.now().Add(d); o.deadline.IsZero() || t.After(o.deadline) {
+		o.deadline = t
+		o.reset_deadline()
+	}
+}
+
+// slide_deadline moves the deadline to o.slide from now. wait_lock must be
+// held.
+//
+func (o *Group) slide_deadline() {
+	if o.slide <= 0 {
+		return
+	}
+	if t := o.now().Add(o.slide); t.After(o.deadline) {
+		o.deadline = t
+		o.reset_deadline()
+	}
+}
+
+func (o *Group) reset_deadline() {
+	if o.slide_timer == nil {
+		o.slide_timer = o.after_func(o.deadline.Sub(o.now()), o.expire)
+		return
+	}
+	o.slide_timer.Reset(o.deadline.Sub(o.now()))
+}
+
+func (o *Group) expire() {
+	o.wait_lock.Lock()
+	if left := o.deadline.Sub(o.now()); 0 < left {
+		o.slide_timer.Reset(left)
+		o.wait_lock.Unlock()
+		return
+	}
+	o.wait_lock.Unlock()
+	if o.Err() != nil {
+		return
+	}
+	if o.log != nil {
+		o.log.Info("deadline exceeded")
+	}
+	o.Cancel()
+}
@@ -0,0 +1,50 @@
+package gogroup
+
+import "errors"
+
+var Err_draining = errors.New("gogroup: group is draining")
+
+// Err_done is returned by Submit() after the Group is canceled and all
+// tasks have ended.
+//
+var Err_done = errors.New("gogroup: group is done")
+
+// Drain stops the Group from taking new tasks while running tasks end
+// without being canceled. Submit() returns Err_draining, Go() drops the task
+// and Register() returns 0, for which Unregister() does nothing. Helpers
+// such as Map(), Go_result() and Race() deliver Err_draining as the result of
+// the refused task. The Group is canceled when the last task ends.
+//
+func (o *Group) Drain() {
+	o.wait_lock.Lock()
+	o.draining = true
+	o.close_fast()
+	idle := o.wait_register.len() == 0 && o.joins == 0 && o.fast_len() == 0
+	o.wait_lock.Unlock()
+	if o.log != nil {
+		o.log.Info("group draining")
+	}
+	if idle {
+		o.Cancel()
+	}
+}
+
+// Is_draining reports if Drain() was called.
+//
+func (o *Group) Is_draining() bool {
+	o.wait_lock.Lock()
+	defer o.wait_lock.Unlock()
+	return o.draining
+}
+
+// closed returns why a task cannot be registered. wait_lock must be held.
+//
+func (o *Group) closed() error {
+	switch {
+	case o.draining:
+		return Err_draining
+	case o.wait_register.len() == 0 && o.fast_len() == 0 && o.Err() != nil:
+		return Err_done
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package gogroup
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRegister_after_wait(t *testing.T) {
+	g := New(With_cancel_nowait(context.Background()))
+	g.Go(func() error { return nil })
+	if err := g.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	if index := g.Register(); index != 0 {
+		t.Fatalf("Register() = %v after Wait(), want 0", index)
+	}
+	ran := false
+	g.Go(func() error { ran = true; return nil })
+	if err := g.Submit("late", func() error { return nil }); !errors.Is(err, Err_done) {
+		t.Fatalf("Submit() = %v, want Err_done", err)
+	}
+	if err := g.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	if ran {
+		t.Fatal("Go() ran a task after Wait()")
+	}
+}
+
+func TestRegister_after_cancel(t *testing.T) {
+	g := New(With_cancel_nowait(context.Background()))
+	index := g.Register()
+	g.Cancel()
+	// A running task keeps the Group open for cleanup tasks.
+	if i := g.Register(); i == 0 {
+		t.Fatal("Register() = 0 while a task is running")
+	} else {
+		g.Unregister(i)
+	}
+	g.Unregister(index)
+	if i := g.Register(); i != 0 {
+		t.Fatalf("Register() = %v after the last task, want 0", i)
+	}
+	f := Go_result(g, func(ctx context.Context) (int, error) { return 1, nil })
+	select {
+	case <-f.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Go_result() Future not done")
+	}
+	if _, err := f.Result(); !errors.Is(err, Err_done) {
+		t.Fatalf("Result() = %v, want Err_done", err)
+	}
+	if _, err := Map(g, []int{1, 2}, 2, func(ctx context.Context, v int) (int, error) { return v, nil }); !errors.Is(err, Err_done) {
+		t.Fatalf("Map() = %v, want Err_done", err)
+	}
+}
+
+func TestDrain_race_go(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		g := New(With_cancel_nowait(context.Background()))
+		index := g.Register()
+		var wg sync.WaitGroup
+		for j := 0; j < 8; j++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for k := 0; k < 10; k++ {
+					g.Go(func() error { return nil })
+					Race(g, func(ctx context.Context) (int, error) { return 1, nil })
+				}
+			}()
+		}
+		g.Drain()
+		g.Unregister(index)
+		done := make(chan error, 1)
+		go func() { done <- g.Wait() }()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("Wait() did not return after Drain()")
+		}
+		wg.Wait()
+		if index := g.Register(); index != 0 {
+			t.Fatalf("Register() = %v after Drain(), want 0", index)
+		}
+	}
+}
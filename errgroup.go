@@ -0,0 +1,105 @@
+package gogroup
+
+import (
+	"context"
+	"fmt"
+)
+
+// With_errgroup() gives the Group the semantics of
+// golang.org/x/sync/errgroup: a Go(), Go_name() or Submit() task cancels the
+// Group only when it returns an error, and Wait() returns when the tasks
+// have ended, then cancels the Group. Signal handling and
+// Register()/Unregister() are unchanged.
+//
+func With_errgroup() option {
+	return func(o *Group) {
+		o.errgroup = true
+	}
+}
+
+// WithContext is errgroup.WithContext(): it returns a With_errgroup() Group
+// derived from ctx, and the Group as the context for its tasks. Together with
+// Go(), TryGo(), SetLimit() and Wait(), a *Group is a drop-in replacement
+// for *errgroup.Group.
+//
+func WithContext(ctx context.Context) (*Group, context.Context) {
+	r := New(With_cancel_nowait(ctx), With_errgroup())
+	return r, r
+}
+
+// SetLimit is errgroup.Group.SetLimit(): at most n Go() tasks run at once
+// and Go() blocks until one ends. A negative n removes the limit; with n == 0
+// Go() blocks and TryGo() returns false. SetLimit panics if tasks started
+// with Go() are running, or with With_adaptive_limit().
+//
+func (o *Group) SetLimit(n int) {
+	if n < 0 {
+		n = -1
+	}
+	a := o.admission()
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if a.aimd != nil {
+		panic("gogroup: SetLimit() with With_adaptive_limit()")
+	}
+	if 0 < a.running || 0 < len(a.pending) {
+		panic(fmt.Errorf("gogroup: modify limit while %v tasks in the group are still active", a.running+len(a.pending)))
+	}
+	a.limit = n
+	a.slots = nil
+}
+
+// TryGo is errgroup.Group.TryGo(): it calls Go() only when the SetLimit(),
+// With_limit() or With_adaptive_limit() limit allows f to run now, and
+// reports whether it did. f is never queued.
+//
+func (o *Group) TryGo(f func() error) bool {
+	if o.Is_draining() {
+		return false
+	}
+	a := o.admit
+	if a == nil || !a.limited() {
+		return o.spawn("", !o.errgroup, f) == nil
+	}
+	a.acquire_slots()
+	select {
+	case a.slots <- struct{}{}:
+	default:
+		return false
+	}
+	return a.try_admit(o, f)
+}
+
+// With_ignore_canceled() makes Set_err() ignore a context.Canceled error
+// once the Group is canceled, so a task returning ctx.Err() after another
+// task ended does not become the Wait() error; Wait() returns nil when only
+// context.Canceled occurred.
+//
+func With_ignore_canceled() option {
+	return func(o *Group) {
+		o.no_canceled = true
+	}
+}
+
+// Task_err returns the first error passed to Set_err(), usually from a task.
+// It is Get_err(). Err() is the context error of the embedded Context.
+//
+func (o *Group) Task_err() error {
+	return o.Get_err()
+}
+
+// Errors returns a copy of the non-nil errors passed to Set_err() so far, in
+// order. It is safe to call while the Group is running.
+//
+func (o *Group) Errors() []error {
+	o.wait_lock.Lock()
+	defer o.wait_lock.Unlock()
+	return append([]error(nil), o.all_errors...)
+}
+
+// Group_err returns why the Group context was canceled, the
+// context.Cause() of the Group, or nil while it is not canceled.
+//
+func (o *Group) Group_err() error {
+	return context.Cause(o)
+}
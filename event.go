@@ -0,0 +1,98 @@
+package gogroup
+
+import (
+	"os"
+	"time"
+)
+
+// Event_buffer is the size of the Events() channel. Events are dropped when
+// it is full.
+//
+var Event_buffer = 100
+
+// Event_kind is the kind of an Event.
+//
+type Event_kind int
+
+const (
+	Event_task_started  Event_kind = iota // Register() or Go()
+	Event_task_finished                   // Unregister() or a Go() func returned
+	Event_canceled                        // the Group Context is canceled
+	Event_signal                          // a signal canceled the Group
+	Event_waited                          // Wait() returned; the last Event
+)
+
+func (o Event_kind) String() string {
+	switch o {
+	case Event_task_started:
+		return "task started"
+	case Event_task_finished:
+		return "task finished"
+	case Event_canceled:
+		return "canceled"
+	case Event_signal:
+		return "signal"
+	case Event_waited:
+		return "waited"
+	}
+	return "unknown"
+}
+
+// Event is a Group lifecycle event sent to Events().
+//
+type Event struct {
+	Kind   Event_kind
+	Time   time.Time
+	Task   Task_info // Event_task_started, Event_task_finished
+	Err    error     // task error, context.Cause() for Event_canceled, or the Wait() error
+	Signal os.Signal // Event_signal; nil for With_notify_context()
+}
+
+// Events returns a channel of the lifecycle events of the Group from the
+// first Events() call on, so supervisors, TUIs and tests can observe the
+// Group without polling. The channel is closed after Event_waited. Events
+// are dropped when the receiver does not keep up; see Event_buffer.
+//
+func (o *Group) Events() <-chan Event {
+	o.wait_lock.Lock()
+	defer o.wait_lock.Unlock()
+	if o.events == nil {
+		o.events = make(chan Event, Event_buffer)
+		if o.events_closed {
+			close(o.events)
+		}
+	}
+	return o.events
+}
+
+// emit sends e to Events() without blocking. wait_lock must be held.
+//
+func (o *Group) emit(e Event) {
+	if o.events == nil || o.events_closed {
+		return
+	}
+	e.Time = time.Now()
+	select {
+	case o.events <- e:
+	default:
+	}
+}
+
+// emit_finished emits Event_task_finished for t. wait_lock must be held.
+//
+func (o *Group) emit_finished(t *task, index int, err error) {
+	info := t.info(index)
+	info.Stop = time.Now()
+	o.emit(Event{Kind: Event_task_finished, Task: info, Err: err})
+}
+
+// close_events sends Event_waited and closes Events(). wait_lock must be
+// held.
+//
+func (o *Group) close_events(err error) {
+	o.emit(Event{Kind: Event_waited, Err: err})
+	if o.events != nil && !o.events_closed {
+		close(o.events)
+	}
+	o.events_closed = true
+}
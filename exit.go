@@ -0,0 +1,51 @@
+package gogroup
+
+import (
+	"context"
+	"errors"
+)
+
+// Err_interrupted stands for a signal in Exit_code() mappings. Run() passes
+// it when the Group was interrupted.
+//
+var Err_interrupted = errors.New("gogroup: interrupted")
+
+// Exit_mapping maps errors matching Err, with errors.Is(), to Code.
+//
+type Exit_mapping struct {
+	Err  error
+	Code int
+}
+
+// Exit_codes are the Exit_code() mappings, checked in order. Change them in
+// main() before Run() for other conventions.
+//
+var Exit_codes = []Exit_mapping{
+	{Err_interrupted, 130},
+	{context.DeadlineExceeded, 124},
+	{Err_max_lifetime, 124},
+}
+
+// Exit_error is the Exit_code() of an error without a mapping.
+//
+var Exit_error = 1
+
+// Exit_code returns a conventional shell exit code for err: 0 for nil, the
+// code of the first matching Exit_codes mapping, the ExitCode() of an error
+// having one, e.g. *exec.ExitError from Go_cmd(), else Exit_error.
+//
+func Exit_code(err error) int {
+	if err == nil {
+		return 0
+	}
+	for _, m := range Exit_codes {
+		if errors.Is(err, m.Err) {
+			return m.Code
+		}
+	}
+	var ec interface{ ExitCode() int }
+	if errors.As(err, &ec) && 0 < ec.ExitCode() {
+		return ec.ExitCode()
+	}
+	return Exit_error
+}
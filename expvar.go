@@ -0,0 +1,13 @@
+package gogroup
+
+import "expvar"
+
+// Publish_expvar publishes Group.Stats() with expvar under name. The stats are
+// read live on every request to /debug/vars. Will panic if name is already
+// published.
+//
+func (o *Group) Publish_expvar(name string) {
+	expvar.Publish(name, expvar.Func(func() interface{} {
+		return o.Stats()
+	}))
+}
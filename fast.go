@@ -0,0 +1,145 @@
+package gogroup
+
+// fast_closed is set in Group.fast when no more fast tasks are started; the
+// lower bits count the running fast tasks.
+//
+const fast_closed = 1 << 62
+
+// With_fast_go() starts Go() tasks without wait_lock or an allocation: they
+// are counted, not registered. A fast task is not in Tasks(), Running(),
+// Dump(), Events(), Stats().Tasks, Stats().Registered or Progress(), and
+// has no Task_info, so a leaked one is not reported. Len(), State() and
+// Stats().Active count them, Wait() and Drain() wait for them, and errors
+// and With_recover() work as for other tasks. Go_name(), Submit() and
+// Register() are unchanged. Go() takes the usual path with a limit,
+// With_sequential(), With_logger(), With_slow_task(), With_idle_timeout(),
+// With_sliding_timeout(), With_load_shedding(), With_leak_report(),
+// With_shutdown_watchdog() or Task_goroutine, and after Drain() or the
+// cancelation of the Group.
+//
+func With_fast_go() option {
+	return func(o *Group) {
+		o.fast_go = true
+	}
+}
+
+// fast_make makes the channels of fast tasks when the options allow them.
+//
+func (o *Group) fast_make() {
+	if o.fast_go = o.fast_ok(); o.fast_go {
+		o.fast_work = make(chan func() error)
+		o.fast_stop = make(chan struct{})
+	}
+}
+
+// fast_ok reports if the options given to New() allow fast tasks.
+//
+func (o *Group) fast_ok() bool {
+	return o.fast_go && !o.sequential && o.log == nil && o.slow_hook == nil && o.idle <= 0 && o.slide <= 0 && o.shed_hook == nil && !o.stacks && !o.goids
+}
+
+// go_fast starts f as a fast task, or reports false when f must take the
+// usual path.
+//
+func (o *Group) go_fast(f func() error) bool {
+	if o.admit != nil || Task_goroutine {
+		return false
+	}
+	for {
+		s := o.fast.Load()
+		if s&fast_closed != 0 {
+			return false
+		}
+		if o.fast.CompareAndSwap(s, s+1) {
+			break
+		}
+	}
+	if !o.fast_started.Load() {
+		o.fast_started.Store(true)
+	}
+	if o.errgroup {
+		o.tasks.Add(1)
+	}
+	select {
+	case o.fast_work <- f:
+	default:
+		// fast_closed is not set before this task ends, so wg() is held.
+		o.wg().Add(1)
+		go o.fast_worker(f)
+	}
+	return true
+}
+
+// fast_worker runs f, then the fast tasks handed to it while idle, until
+// close_fast().
+//
+func (o *Group) fast_worker(f func() error) {
+	defer o.wg().Done()
+	for {
+		o.exec_fast(f)
+		select {
+		case f = <-o.fast_work:
+		case <-o.fast_stop:
+			return
+		}
+	}
+}
+
+func (o *Group) exec_fast(f func() error) {
+	stack, err := o.run_recover("", f)
+	if err != nil {
+		o.set_err(err, o.task_error(0, "", err, stack))
+	}
+	if o.errgroup {
+		o.tasks.Done()
+	}
+	if !o.errgroup || err != nil {
+		o.Cancel()
+	}
+	if o.fast.Add(-1) == fast_closed {
+		o.fast_ended()
+	}
+}
+
+// close_fast stops fast tasks from starting, at cancelation or Drain(). The
+// running ones hold wg() until the last ends. wait_lock must be held, so the
+// cancel hook, which sets fast_closed before its own wg().Done(), still
+// holds wg() when Drain() adds to it.
+//
+func (o *Group) close_fast() {
+	if o.fast.Load()&fast_closed != 0 {
+		return
+	}
+	o.wg().Add(1)
+	if o.fast_stop != nil {
+		close(o.fast_stop)
+	}
+	for {
+		s := o.fast.Load()
+		if o.fast.CompareAndSwap(s, s|fast_closed) {
+			if s == 0 {
+				o.wg().Done()
+			}
+			return
+		}
+	}
+}
+
+// fast_ended releases wg() when the last fast task has ended after
+// close_fast(), and ends a drained Group.
+//
+func (o *Group) fast_ended() {
+	o.wait_lock.Lock()
+	idle := o.draining && o.wait_register.len() == 0 && o.joins == 0
+	o.wait_lock.Unlock()
+	if idle {
+		o.Cancel()
+	}
+	o.wg().Done()
+}
+
+// fast_len returns the number of running fast tasks.
+//
+func (o *Group) fast_len() int {
+	return int(o.fast.Load() &^ fast_closed)
+}
@@ -0,0 +1,90 @@
+package gogroup
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGo_fast(t *testing.T) {
+	e := errors.New("fast")
+	g := New(With_cancel_nowait(context.Background()), With_errgroup(), With_fast_go())
+	var n atomic.Int32
+	for i := 0; i < 100; i++ {
+		g.Go(func() error {
+			n.Add(1)
+			return nil
+		})
+	}
+	g.Go(func() error { return e })
+	if err := g.Wait(); err != e {
+		t.Fatalf("Wait() = %v, want %v", err, e)
+	}
+	if n.Load() != 100 {
+		t.Fatalf("%v tasks ran, want 100", n.Load())
+	}
+	if l := g.Len(); l != 0 {
+		t.Fatalf("Len() = %v after Wait(), want 0", l)
+	}
+}
+
+func TestGo_fast_drain(t *testing.T) {
+	g := New(With_cancel_nowait(context.Background()), With_errgroup(), With_fast_go())
+	release := make(chan struct{})
+	g.Go(func() error {
+		<-release
+		return nil
+	})
+	if l := g.Len(); l != 1 {
+		t.Fatalf("Len() = %v, want 1", l)
+	}
+	g.Drain()
+	if g.Err() != nil {
+		t.Fatal("Drain() canceled the Group with a fast task running")
+	}
+	ran := false
+	g.Go(func() error { ran = true; return nil })
+	close(release)
+	select {
+	case <-g.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("Drain() did not cancel the Group after the last fast task")
+	}
+	g.Wait()
+	if ran {
+		t.Fatal("Go() ran a task after Drain()")
+	}
+}
+
+func TestGo_fast_cancel_race(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		g := New(With_cancel_nowait(context.Background()), With_fast_go())
+		index := g.Register()
+		var wg sync.WaitGroup
+		for j := 0; j < 8; j++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for k := 0; k < 20; k++ {
+					g.Go(func() error { return nil })
+				}
+			}()
+		}
+		g.Cancel()
+		g.Unregister(index)
+		done := make(chan error, 1)
+		go func() { done <- g.Wait() }()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("Wait() did not return")
+		}
+		wg.Wait()
+		if l := g.Len(); l != 0 {
+			t.Fatalf("Len() = %v after Wait(), want 0", l)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package gogroup
+
+import (
+	"context"
+	"runtime/debug"
+)
+
+// Future is the pending result of a Go_result() task.
+//
+type Future[T any] struct {
+	done   chan struct{}
+	val    T
+	err    error
+	cancel context.CancelFunc
+}
+
+// Go_result runs f as a task and returns its Future. f is called with a
+// context canceled by g or Future.Cancel(). Unlike Go(), a task ending
+// without an error does not cancel g. An error is passed to Set_err() and
+// cancels g, unless the task was canceled with Future.Cancel(). A panic in
+// f makes the Future error a *Panic_error, then goes on to With_recover().
+//
+func Go_result[T any](g *Group, f func(ctx context.Context) (T, error)) *Future[T] {
+	ctx, cancel := context.WithCancel(g)
+	r := &Future[T]{done: make(chan struct{}), cancel: cancel}
+	if err := g.spawn("", false, func() error {
+		defer cancel()
+		defer func() {
+			// A panic, recovered by With_recover() or not, is the result.
+			if p := recover(); p != nil {
+				r.err = &Panic_error{Value: p, Stack: debug.Stack()}
+				close(r.done)
+				panic(p)
+			}
+		}()
+		r.val, r.err = f(ctx)
+		close(r.done)
+		if r.err != nil && ctx.Err() != nil && g.Err() == nil {
+			return nil
+		}
+		return r.err
+	}); err != nil {
+		cancel()
+		r.err = err
+		close(r.done)
+	}
+	return r
+}
+
+// Done is closed when the task has returned.
+//
+func (o *Future[T]) Done() <-chan struct{} {
+	return o.done
+}
+
+// Result blocks until the task has returned and returns its result.
+//
+func (o *Future[T]) Result() (T, error) {
+	<-o.done
+	return o.val, o.err
+}
+
+// Cancel cancels the context of the task only.
+//
+func (o *Future[T]) Cancel() {
+	o.cancel()
+}
@@ -8,11 +8,14 @@ package gogroup
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"os"
-	"os/signal"
+	"runtime"
+	"runtime/debug"
 	"sync"
-	"syscall"
+	"sync/atomic"
 	"time"
 )
 
@@ -38,9 +41,31 @@ func With_cancel_nowait(ctx context.Context) option {
 	}
 }
 
+// Use With_values_only() as the context to New() for cleanup or audit tasks
+// that must complete after ctx is canceled. The child has the values of ctx,
+// but not its cancellation or deadline. Use With_joined_child() for a parent
+// Group to Wait() for the child.
+//
+// parent.Cancel() will not call child.Cancel().
+// child.Cancel() will not call parent.Cancel().
+//
+func With_values_only(ctx context.Context) option {
+	return func(o *Group) {
+		switch {
+		case o.Context != nil:
+			panic("context already set")
+		case ctx == nil:
+			panic("ctx is nil")
+		default:
+			o.Context, o.CancelFunc = context.WithCancel(context.WithoutCancel(ctx))
+			o.values_only = true
+		}
+	}
+}
+
 // Use With_cancel() as the context to New(). Will panic if context is already
-// set. Will panic if parent is nil. Register/Unregister use the parent's
-// WaitGroup.
+// set. Will panic if parent is nil. parent.Wait() waits for the child's
+// Register/Unregister tasks until Detach().
 //
 // parent goroutine will not wait on the child.
 // parent.Cancel() will call child.Cancel().
@@ -54,8 +79,8 @@ func With_cancel(parent *Group) option {
 		case parent == nil:
 			panic("parent is nil")
 		default:
-			o.Context, o.CancelFunc = context.WithCancel(parent)
-			o.parent = parent
+			o.Context, o.CancelFunc = context.WithCancel(context.WithoutCancel(parent))
+			o.attach(parent)
 		}
 	}
 }
@@ -73,9 +98,11 @@ func With_timeout_nowait(ctx context.Context, timeout time.Duration) option {
 		case o.Context != nil:
 			panic("context already set")
 		case ctx == nil:
-			o.Context, o.CancelFunc = context.WithTimeout(context.Background(), timeout)
+			ctx = context.Background()
+			fallthrough
 		default:
-			o.Context, o.CancelFunc = context.WithTimeout(ctx, timeout)
+			o.Context = ctx
+			o.expires = func(now time.Time) time.Time { return now.Add(timeout) }
 		}
 	}
 }
@@ -88,6 +115,45 @@ func With_timeout_nowait(ctx context.Context, timeout time.Duration) option {
 // child timeout will not cancel parent.
 //
 func With_timeout(parent *Group, timeout time.Duration) option {
+	return with_deadline(parent, func(now time.Time) time.Time { return now.Add(timeout) })
+}
+
+// Use With_deadline_nowait() as the context to New() when an absolute
+// deadline is already known, e.g. from an upstream request.
+//
+// parent goroutine will not wait on the child.
+// parent.Cancel() will call child.Cancel().
+// child.Cancel() will not call parent.Cancel().
+// child deadline will not cancel parent.
+//
+func With_deadline_nowait(ctx context.Context, t time.Time) option {
+	return func(o *Group) {
+		switch {
+		case o.Context != nil:
+			panic("context already set")
+		case ctx == nil:
+			ctx = context.Background()
+			fallthrough
+		default:
+			o.Context = ctx
+			o.expires = func(time.Time) time.Time { return t }
+		}
+	}
+}
+
+// Will panic if parent is nil or context is already set. A parent deadline
+// earlier than t is kept. parent.Wait() waits for the child's
+// Register/Unregister tasks until Detach().
+//
+// parent.Cancel() will call child.Cancel().
+// child.Cancel() will not call parent.Cancel().
+// child deadline will not cancel parent.
+//
+func With_deadline(parent *Group, t time.Time) option {
+	return with_deadline(parent, func(time.Time) time.Time { return t })
+}
+
+func with_deadline(parent *Group, expires func(now time.Time) time.Time) option {
 	return func(o *Group) {
 		switch {
 		case o.Context != nil:
@@ -95,12 +161,35 @@ func With_timeout(parent *Group, timeout time.Duration) option {
 		case parent == nil:
 			panic("parent is nil")
 		default:
-			o.Context, o.CancelFunc = context.WithTimeout(parent, timeout)
-			o.parent = parent
+			o.Context = context.WithoutCancel(parent)
+			o.expires = func(now time.Time) time.Time {
+				t := expires(now)
+				if d, ok := parent.Deadline(); ok && d.Before(t) {
+					t = d
+				}
+				return t
+			}
+			o.attach(parent)
 		}
 	}
 }
 
+// Err_no_tasks is suggested for With_empty_wait().
+//
+var Err_no_tasks = errors.New("gogroup: Wait() called before any task was registered")
+
+// With_empty_wait() prevents Wait() from blocking forever when no task was
+// ever registered with Register() or Go(). Wait() will cancel the Group and
+// return err instead; a nil err lets Wait() complete immediately without an
+// error. Without With_empty_wait(), Wait() blocks until Cancel(), a timeout,
+// or a signal.
+//
+func With_empty_wait(err error) option {
+	return func(o *Group) {
+		o.empty_wait, o.empty_err = true, err
+	}
+}
+
 // A Group is a collection of goroutines working on subtasks that are part of
 // the same overall task.
 //
@@ -108,107 +197,426 @@ type Group struct {
 	context.Context
 	context.CancelFunc
 	Interrupted   bool
-	parent        *Group
-	local_wg      *sync.WaitGroup // Not used when parent is present
+	parent        *Group // With_cancel(), With_timeout() until Detach()
+	unlink        func() bool
+	local_wg      sync.WaitGroup
 	err_once      sync.Once
 	err           error
 	wait_lock     sync.Mutex
 	wait_index    int
-	wait_register map[int]bool
+	wait_register registry
+	cpu_time      bool
+	cpu           map[string]time.Duration
+	log           *slog.Logger
+	errors        int
+	canceled      time.Time
+	shutdown      time.Duration
+	slow          time.Duration
+	slow_hook     func(Task_info)
+	history       []Task_info // ring of ended tasks; history_next is the oldest
+	history_next  int
+	goids         bool // With_shutdown_watchdog()
+	stacks        bool // With_leak_report()
+	empty_wait    bool
+	empty_err     error
+	restarts      int
+	breakers      map[string]*Breaker
+	limiter       Limiter
+	sema          *sema
+	keyed         map[string][]func(ctx context.Context) error
+	once          map[string]*once_call
+	hedge_calls   int
+	hedges        int
+	admit         *admission
+	done_units    int64
+	total_units   int64
+	name          string
+	up            *Group // Group the context is derived from
+	children      []*Group
+	stopping      []*Group // canceled children, for Running()
+	joined        bool
+	unjoin        func()         // ends the join of up
+	joins         int            // joined children not ended
+	join_wg       sync.WaitGroup // joined children
+	slide         time.Duration
+	deadline      time.Time
+	slide_timer   Timer
+	idle          time.Duration
+	idle_since    time.Time
+	idle_timer    Timer
+	lifetime      time.Duration
+	cancel_cause  context.CancelCauseFunc
+	values        [][2]interface{}
+	notify        context.Context // With_notify_context()
+	notify_parent context.Context
+	notify_stop   context.CancelFunc
+	signals       []os.Signal // With_notify_context()
+	errgroup      bool
+	tasks         sync.WaitGroup // registered tasks
+	dead          chan struct{}
+	probes        map[string]*probe
+	phases        []*Group
+	phase_lock    sync.Mutex
+	no_signal     bool
+	on_start      []func(ctx context.Context) error
+	pre_stop      []func(ctx context.Context) error
+	draining      bool
+	graces        []grace
+	grace_timers  []*time.Timer
+	waited        bool
+	wait_once     sync.Once
+	wait_err      error
+	no_canceled   bool
+	recover_hook  func(string, interface{}, []byte) error
+	all_errors    []error
+	on_done       []func(error)
+	defers        []func()
+	memory        *memory_monitor
+	values_only   bool
+	parent_signal bool // signals are watched by up
+	clock         Clock
+	sequential    bool
+	sequence      []queued
+	sequenced     bool // run_sequence() has run
+	events        chan Event
+	events_closed bool
+	control       string // With_control_socket() path
+	pidfile       string
+	drain_signal  os.Signal
+	id            string
+	shed_hook     func(string, Load) bool
+	latency       time.Duration                 // With_load_shedding()
+	expires       func(now time.Time) time.Time // timeout options
+	fast_go       bool                          // With_fast_go()
+	fast          atomic.Int64                  // fast tasks, fast_closed
+	fast_started  atomic.Bool
+	fast_work     chan func() error // to idle fast_worker()
+	fast_stop     chan struct{}     // closed by close_fast()
 }
 
 // New returns a Group using with zero or more options. If a context is not
 // provided in an option, With_cancel() will be used. The Group.Context is
 // canceled when either a Go() func returns or a func using Register()/Unregister().
-// New must be called to make a Group.
+// New must be called to make a Group. A Group is used once: to run another
+// batch after Wait() returns, call New() again with the same options.
 //
 func New(opt ...option) (r *Group) {
-	r = &Group{wait_register: map[int]bool{}}
+	r = &Group{}
 	for _, o := range opt {
 		o(r)
 	}
+	r.fast_make()
+	if r.notify_parent != nil {
+		r.with_notify()
+	}
+	if r.clock == nil {
+		if up, ok := From_context(r.Context); ok {
+			r.clock = up.clock
+		}
+	}
+	if r.expires != nil {
+		r.with_deadline()
+	}
 	if r.CancelFunc == nil {
 		With_cancel_nowait(context.Background())(r)
 	}
-	if r.parent == nil {
-		r.local_wg = &sync.WaitGroup{}
+	r.with_values()
+	r.with_cause()
+	if 0 < r.slide {
+		r.Extend_deadline(r.slide)
+	}
+	r.wait_lock.Lock()
+	r.idle_start()
+	r.wait_lock.Unlock()
+	r.start_lifetime()
+	r.start_memory_monitor()
+	r.start_control()
+	r.start_pidfile()
+	if up, ok := r.Context.Value(group_key{}).(*Group); ok {
+		r.up = up
+		up.add_child(r)
+		r.join()
+	}
+	switch {
+	case r.notify != nil || r.no_signal:
+	case r.up != nil && !r.values_only:
+		// The parent watches signals and cancels the child.
+		r.parent_signal = true
+	default:
+		watch_signals(r)
 	}
+	r.watch_drain_signal()
 	r.wg().Add(1)
-	go func() {
+	context.AfterFunc(r, func() {
 		defer r.wg().Done()
-		ch := make(chan os.Signal, 1)
-		defer close(ch)
-		signal.Notify(ch, os.Interrupt, syscall.SIGTERM)
-		defer signal.Stop(ch)
-		select {
-		case <-r.Done():
-		case <-ch:
+		r.wait_lock.Lock()
+		r.close_fast()
+		r.wait_lock.Unlock()
+		unwatch_signals(r)
+		if r.notify != nil && r.notify.Err() != nil && r.notify_parent.Err() == nil {
+			r.interrupt()
+			r.wait_lock.Lock()
+			r.emit(Event{Kind: Event_signal})
+			r.wait_lock.Unlock()
+		}
+		r.wait_lock.Lock()
+		up, parent_signal := r.up, r.parent_signal
+		r.wait_lock.Unlock()
+		if parent_signal && up != nil && up.Is_interrupted() {
+			r.wait_lock.Lock()
 			r.Interrupted = true
-			fmt.Fprintf(os.Stderr, "%v", Line_end)
+			r.wait_lock.Unlock()
+		}
+		if r.notify_stop != nil {
+			r.notify_stop()
 		}
 		r.Cancel()
-	}()
+		r.wait_lock.Lock()
+		r.canceled = time.Now()
+		r.emit(Event{Kind: Event_canceled, Err: context.Cause(r)})
+		r.wait_lock.Unlock()
+		r.start_graces()
+		if up != nil {
+			up.stop_child(r)
+		}
+		if r.log != nil {
+			r.log.Info("group canceled", "cause", context.Cause(r), "err", r.Get_err())
+		}
+	})
+	r.run_on_start()
 	return
 }
 
+func (o *Group) interrupt() {
+	o.wait_lock.Lock()
+	o.Interrupted = true
+	o.wait_lock.Unlock()
+	if o.log == nil {
+		fmt.Fprintf(os.Stderr, "%v", Line_end)
+	} else {
+		o.log.Info("signal received")
+	}
+}
+
 func (o *Group) Cancel() {
 	if o.CancelFunc != nil {
 		o.CancelFunc()
 	}
 }
 
+// Cancel_err passes err to Set_err(), then cancels the Group with err as the
+// context.Cause(), for canceling because of something outside a task.
+//
+func (o *Group) Cancel_err(err error) {
+	o.Set_err(err)
+	o.cancel(err)
+}
+
 // Wait blocks until all function calls from the Go method have returned, then
-// returns the first non-nil error (if any) from them.
+// returns the first non-nil error (if any) from them. Wait is safe to call
+// from several goroutines; every call returns the same error.
 //
 func (o *Group) Wait() error {
+	o.wait_once.Do(func() {
+		o.wait_err = o.wait()
+	})
+	return o.wait_err
+}
+
+func (o *Group) wait() error {
+	o.run_sequence()
+	if o.errgroup {
+		o.tasks.Wait()
+		o.Cancel()
+	}
+	if o.empty_wait {
+		o.wait_lock.Lock()
+		empty := o.wait_index == 0 && !o.fast_started.Load()
+		o.wait_lock.Unlock()
+		if empty {
+			if o.empty_err != nil {
+				o.Set_err(o.empty_err)
+			}
+			o.Cancel()
+		}
+	}
 	<-o.Done()
+	defer o.stop_graces()
 	o.wg().Wait()
+	o.join_wg.Wait()
 	o.Cancel()
-	return o.err
+	o.run_defers()
+	err := o.Get_err()
+	o.run_on_done(err)
+	o.wait_lock.Lock()
+	if o.shutdown == 0 {
+		o.shutdown = time.Since(o.canceled)
+	}
+	o.waited = true
+	o.close_events(err)
+	o.wait_lock.Unlock()
+	return err
 }
 
 func (o *Group) wg() *sync.WaitGroup {
-	if o.parent != nil {
-		return o.parent.wg()
+	return &o.local_wg
+}
+
+// hold adds a goroutine that Wait() waits for, such as a hook, unless the
+// Group is canceled and all tasks have ended, as Wait() may be returning.
+// Unlike Register() it holds no task, so it is not in Tasks() and does not
+// keep Drain() from canceling the Group. wait_lock must be held.
+//
+func (o *Group) hold() bool {
+	if o.wait_register.len() == 0 && o.Err() != nil {
+		return false
 	}
-	return o.local_wg
+	o.wg().Add(1)
+	return true
 }
 
 // Register increments the internal sync.WaitGroup. Unregister() must be
 // called with the returned int to end Group.Wait(). goroutines using
 // Register/Unregister must end upon receipt from the Group.Ctx.Done()
-// channel.
+// channel. Register returns 0 after Drain(), or after the Group is canceled
+// and all tasks have ended, so a late Register() cannot restart Wait().
+// Unregister(0) does nothing.
 //
 func (o *Group) Register() int {
+	return o.register("", true)
+}
+
+// Register_request registers a named task, such as a request, that does not
+// cancel the Group when it ends. It returns Err_draining after Drain(), or
+// Err_done after the Group is canceled and all tasks have ended, so the
+// request can be rejected. Unregister() must be called with the returned int.
+//
+func (o *Group) Register_request(name string) (int, error) {
+	return o.try_register(name, false)
+}
+
+func (o *Group) register(name string, cancel bool) int {
+	index, _ := o.try_register(name, cancel)
+	return index
+}
+
+// try_register registers a task, or returns closed().
+//
+func (o *Group) try_register(name string, cancel bool) (int, error) {
+	t := task{name: name, start: time.Now(), keep: !cancel}
+	if Task_caller {
+		runtime.Callers(2, t.pcs[:])
+	}
+	if o.goids || Task_goroutine {
+		t.goid = goid()
+	}
+	if o.stacks {
+		t.stack = debug.Stack()
+	}
 	o.wait_lock.Lock()
-	defer o.wait_lock.Unlock()
+	if err := o.closed(); err != nil {
+		o.wait_lock.Unlock()
+		return 0, err
+	}
 	o.wg().Add(1)
+	o.tasks.Add(1)
 	o.wait_index++
-	o.wait_register[o.wait_index] = true
-	return o.wait_index
+	index, p := o.wait_register.add(&t)
+	if o.events != nil {
+		o.emit(Event{Kind: Event_task_started, Task: p.info(index)})
+	}
+	o.idle_stop()
+	if o.slow_hook != nil {
+		info := p.info(index)
+		p.slow = time.AfterFunc(o.slow, func() {
+			o.slow_hook(info)
+		})
+	}
+	o.wait_lock.Unlock()
+	if o.log != nil {
+		o.log.Debug("task start", "index", index, "name", name)
+	}
+	return index, nil
 }
 
 // Unregister decrements the internal sync.WaitGroup and calls
 // Group.Cancel(). It is safe to call Unregister multiple times.
 //
 func (o *Group) Unregister(index int) {
+	o.unregister(index, nil)
+}
+
+func (o *Group) unregister(index int, err error) {
 	o.wait_lock.Lock()
-	defer o.wait_lock.Unlock()
-	if o.wait_register[index] {
-		delete(o.wait_register, index)
+	t, ok := o.wait_register.remove(index)
+	var name string
+	var start time.Time
+	if ok {
+		name, start = t.name, t.start
+		if t.slow != nil {
+			t.slow.Stop()
+		}
+		o.add_history(t, index)
+		if o.shed_hook != nil {
+			o.measure(time.Since(t.start))
+		}
+		if o.events != nil {
+			o.emit_finished(t, index, err)
+		}
 		o.wg().Done()
-		o.Cancel()
+		o.tasks.Done()
+		o.slide_deadline()
+		o.idle_start()
+		if !t.keep || err != nil || o.draining && o.wait_register.len() == 0 && o.joins == 0 && o.fast_len() == 0 {
+			o.Cancel()
+		}
+	}
+	log := o.log
+	o.wait_lock.Unlock()
+	if ok && log != nil {
+		log_done(log, index, name, start, err)
+	}
+}
+
+// log_done is apart from unregister(), which runs on the small stack of
+// every task goroutine.
+//
+func log_done(log *slog.Logger, index int, name string, start time.Time, err error) {
+	if err == nil {
+		log.Debug("task done", "index", index, "name", name, "duration", time.Since(start))
+	} else {
+		log.Error("task done", "index", index, "name", name, "duration", time.Since(start), "err", err)
 	}
 }
 
 // Set_err will return the first called
 //
 func (o *Group) Set_err(err error) {
+	o.set_err(err, err)
+}
+
+// set_err is Set_err() with e, err or an error wrapping it, kept for
+// Errors().
+//
+func (o *Group) set_err(err, e error) {
+	if o.no_canceled && errors.Is(err, context.Canceled) && o.Err() != nil {
+		return
+	}
+	if err != nil {
+		o.wait_lock.Lock()
+		o.errors++
+		o.all_errors = append(o.all_errors, e)
+		o.wait_lock.Unlock()
+	}
 	o.err_once.Do(func() {
+		o.wait_lock.Lock()
 		o.err = err
+		o.wait_lock.Unlock()
 	})
 }
 
 func (o *Group) Get_err() error {
+	o.wait_lock.Lock()
+	defer o.wait_lock.Unlock()
 	return o.err
 }
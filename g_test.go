@@ -0,0 +1,146 @@
+package gogroup
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func BenchmarkRegisterUnregister(b *testing.B) {
+	defer func(v bool) { Task_caller = v }(Task_caller)
+	Task_caller = false
+	g := New(With_cancel_nowait(context.Background()))
+	defer g.Cancel()
+	// Tasks running for the whole benchmark, as in a server.
+	held := make([]int, 1000)
+	for i := range held {
+		held[i] = g.Register()
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g.Unregister(g.Register())
+	}
+	b.StopTimer()
+	for _, index := range held {
+		g.Unregister(index)
+	}
+}
+
+func TestUnregister_stale(t *testing.T) {
+	g := New(With_cancel_nowait(context.Background()))
+	defer g.Cancel()
+	a := g.register("a", false)
+	g.Unregister(a)
+	b := g.register("b", false)
+	if a == b {
+		t.Fatalf("index %v reused", a)
+	}
+	// The second Unregister() of a must not end b, which took its slot.
+	g.Unregister(a)
+	if n := g.Len(); n != 1 {
+		t.Fatalf("Len() = %v, want 1", n)
+	}
+	g.Unregister(b)
+	if n := g.Len(); n != 0 {
+		t.Fatalf("Len() = %v, want 0", n)
+	}
+}
+
+func BenchmarkGo(b *testing.B) {
+	g, _ := WithContext(context.Background())
+	f := func() error { return nil }
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g.Go(f)
+	}
+	if err := g.Wait(); err != nil {
+		b.Fatal(err)
+	}
+}
+
+func BenchmarkGo_fast(b *testing.B) {
+	g := New(With_cancel_nowait(context.Background()), With_errgroup(), With_fast_go())
+	f := func() error { return nil }
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g.Go(f)
+	}
+	if err := g.Wait(); err != nil {
+		b.Fatal(err)
+	}
+}
+
+// BenchmarkGo_goroutine is the cost of a goroutine and a sync.WaitGroup,
+// which BenchmarkGo_fast adds to.
+//
+func BenchmarkGo_goroutine(b *testing.B) {
+	var wg sync.WaitGroup
+	f := func() error { return nil }
+	run := func() {
+		f()
+		wg.Done()
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		wg.Add(1)
+		go run()
+	}
+	wg.Wait()
+}
+
+func BenchmarkNew(b *testing.B) {
+	ctx := context.Background()
+	// A long lived Group keeps the signal watcher running, as in a program.
+	main := New(With_cancel_nowait(ctx))
+	defer main.Cancel()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g := New(With_cancel_nowait(ctx))
+		g.Cancel()
+		g.Wait()
+	}
+}
+
+func TestWait_task_error(t *testing.T) {
+	sentinel := errors.New("sentinel")
+	g, _ := WithContext(context.Background())
+	g.Go(func() error { return sentinel })
+	if err := g.Wait(); err != sentinel {
+		t.Fatalf("Wait() = %v, want the task error", err)
+	}
+	var te *Task_error
+	if errs := g.Errors(); len(errs) != 1 || !errors.As(errs[0], &te) || te.Err != sentinel {
+		t.Fatalf("Errors() = %v, want one *Task_error", errs)
+	}
+	g = New(With_cancel_nowait(context.Background()), With_recover(func(string, interface{}, []byte) error {
+		return sentinel
+	}))
+	g.Go(func() error { panic("boom") })
+	if err := g.Wait(); err != sentinel {
+		t.Fatalf("Wait() = %v, want the With_recover() error", err)
+	}
+	if errs := g.Errors(); len(errs) != 1 || !errors.As(errs[0], &te) || len(te.Stack) == 0 {
+		t.Fatal("Task_error of a recovered panic has no stack")
+	}
+}
+
+func TestSetLimit_zero(t *testing.T) {
+	g, _ := WithContext(context.Background())
+	g.SetLimit(0)
+	if g.TryGo(func() error { return nil }) {
+		t.Fatal("TryGo() = true with SetLimit(0)")
+	}
+	g.SetLimit(-1)
+	if !g.TryGo(func() error { return nil }) {
+		t.Fatal("TryGo() = false with no limit")
+	}
+	if err := g.Wait(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright 2016 aletheia7. All rights reserved. Use of this source code is
+// governed by a BSD-2-Clause license that can be found in the LICENSE file.
+
+// Package ggrpc runs a grpc.Server under a gogroup.Group.
+//
+//	ggrpc.Go(gg, srv, l, 10*time.Second)
+//
+package ggrpc
+
+import (
+	"net"
+	"time"
+
+	"github.com/aletheia7/gogroup"
+	"google.golang.org/grpc"
+)
+
+// Go serves srv on l as a gogroup.Group.Go() task until g is canceled.
+// srv.GracefulStop() then drains connections for up to grace before
+// srv.Stop() forces them closed. A Serve error is passed to Set_err().
+//
+func Go(g *gogroup.Group, srv *grpc.Server, l net.Listener, grace time.Duration) {
+	g.Go_name("grpc "+l.Addr().String(), func() error {
+		serve := make(chan error, 1)
+		go func() {
+			serve <- srv.Serve(l)
+		}()
+		select {
+		case err := <-serve:
+			return err
+		case <-g.Done():
+		}
+		stopped := make(chan struct{})
+		go func() {
+			srv.GracefulStop()
+			close(stopped)
+		}()
+		t := time.NewTimer(grace)
+		defer t.Stop()
+		select {
+		case <-stopped:
+		case <-t.C:
+			srv.Stop()
+			<-stopped
+		}
+		if err := <-serve; err != grpc.ErrServerStopped {
+			return err
+		}
+		return nil
+	})
+}
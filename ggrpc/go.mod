@@ -0,0 +1,21 @@
+module github.com/aletheia7/gogroup/ggrpc
+
+go 1.21
+
+require (
+	github.com/aletheia7/gogroup v0.0.0-20261016015808-ef773974fb3f
+	google.golang.org/grpc v1.67.1
+)
+
+require (
+	golang.org/x/net v0.28.0 // indirect
+	golang.org/x/sys v0.24.0 // indirect
+	golang.org/x/text v0.17.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240814211410-ddb44dafa142 // indirect
+	google.golang.org/protobuf v1.34.2 // indirect
+)
+
+// Builds in this tree use the gogroup next to it; users get the required
+// commit, one with the APIs used here. Move it forward when newer
+// gogroup APIs are used.
+replace github.com/aletheia7/gogroup => ../
@@ -0,0 +1,56 @@
+package ggrpc
+
+import (
+	"context"
+
+	"github.com/aletheia7/gogroup"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Unary_interceptor registers each RPC with g using
+// gogroup.Group.Register_request(), so g.Wait() drains in-flight RPCs. The
+// RPC Context is canceled when g is canceled, and gogroup.From_context() of
+// it returns g. RPCs fail with codes.Unavailable after g.Drain() or when g
+// is done.
+//
+//	srv := grpc.NewServer(
+//		grpc.UnaryInterceptor(ggrpc.Unary_interceptor(gg)),
+//		grpc.StreamInterceptor(ggrpc.Stream_interceptor(gg)),
+//	)
+//
+func Unary_interceptor(g *gogroup.Group) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		index, err := g.Register_request("grpc " + info.FullMethod)
+		if err != nil {
+			return nil, status.Error(codes.Unavailable, err.Error())
+		}
+		defer g.Unregister(index)
+		ctx, cancel := g.Bind(ctx)
+		defer cancel()
+		return handler(ctx, req)
+	}
+}
+
+// Stream_interceptor is Unary_interceptor() for streaming RPCs.
+//
+func Stream_interceptor(g *gogroup.Group) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		index, err := g.Register_request("grpc " + info.FullMethod)
+		if err != nil {
+			return status.Error(codes.Unavailable, err.Error())
+		}
+		defer g.Unregister(index)
+		ctx, cancel := g.Bind(ss.Context())
+		defer cancel()
+		return handler(srv, &stream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+type stream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (o *stream) Context() context.Context { return o.ctx }
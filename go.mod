@@ -1,5 +1,5 @@
 module github.com/aletheia7/gogroup
 
-go 1.17
+go 1.21
 
 retract v2.1.0+incompatible
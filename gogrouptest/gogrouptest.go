@@ -0,0 +1,68 @@
+// Copyright 2016 aletheia7. All rights reserved. Use of this source code is
+// governed by a BSD-2-Clause license that can be found in the LICENSE file.
+
+// Package gogrouptest has testing helpers for gogroup.
+//
+package gogrouptest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aletheia7/gogroup"
+)
+
+// Verify_timeout is how long Verify_shutdown() waits for tasks to end.
+//
+var Verify_timeout = time.Second
+
+// Enable_stacks makes Verify_shutdown() report the goroutine stack of each
+// task. It sets gogroup.Task_goroutine; call it before the Groups are made,
+// e.g. in TestMain().
+//
+func Enable_stacks() {
+	gogroup.Task_goroutine = true
+}
+
+// Verify_shutdown fails t when g is not canceled, or when tasks of g, or of
+// its child Groups, are still running Verify_timeout after it is called.
+// Call it after g.Wait() returns, e.g. deferred after a deferred g.Wait().
+// The failure has the name of each task, and its goroutine stack after
+// Enable_stacks().
+//
+// Unlike uber-go/goleak, only the tasks of g are checked, so tests may run
+// in parallel.
+//
+func Verify_shutdown(t testing.TB, g *gogroup.Group) {
+	t.Helper()
+	if g.Err() == nil {
+		t.Errorf("gogroup: Verify_shutdown() called before the Group is canceled")
+		return
+	}
+	end := time.Now().Add(Verify_timeout)
+	for {
+		running := g.Running()
+		if len(running) == 0 {
+			return
+		}
+		if time.Now().After(end) {
+			t.Errorf("%v", report(running))
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func report(running []gogroup.Running_task) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "gogroup: %v task(s) still running after Wait()\n", len(running))
+	for _, t := range running {
+		fmt.Fprintf(&b, "task %v %q group %q running %v started at %v\n", t.Index, t.Name, t.Group, t.Duration().Round(time.Millisecond), t.Caller())
+		if 0 < len(t.Stack) {
+			fmt.Fprintf(&b, "%s\n", t.Stack)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,51 @@
+package gogroup
+
+import "time"
+
+// With_grace() calls on_expire with the tasks still running d after the
+// Group is canceled, so a hung shutdown can be logged, dumped or ended with
+// os.Exit(). on_expire is not called when all tasks have ended.
+//
+func With_grace(d time.Duration, on_expire func(outstanding []Task_info)) option {
+	return func(o *Group) {
+		o.graces = append(o.graces, grace{d: d, f: func() {
+			on_expire(o.Tasks())
+		}})
+	}
+}
+
+// grace is a func called d after the Group is canceled while tasks are still
+// registered. With_grace(), With_leak_report() and With_shutdown_watchdog()
+// each add one.
+//
+type grace struct {
+	d time.Duration
+	f func()
+}
+
+func (o *Group) start_graces() {
+	o.wait_lock.Lock()
+	defer o.wait_lock.Unlock()
+	for _, g := range o.graces {
+		f := g.f
+		o.grace_timers = append(o.grace_timers, time.AfterFunc(g.d, func() {
+			o.wait_lock.Lock()
+			n := o.wait_register.len()
+			o.wait_lock.Unlock()
+			if 0 < n {
+				f()
+			}
+		}))
+	}
+}
+
+// stop_graces stops the grace timers once all tasks have ended.
+//
+func (o *Group) stop_graces() {
+	o.wait_lock.Lock()
+	defer o.wait_lock.Unlock()
+	for _, t := range o.grace_timers {
+		t.Stop()
+	}
+	o.grace_timers = nil
+}
@@ -0,0 +1,89 @@
+package gogroup
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// The HTTP headers of Set_headers() and Request_group().
+//
+const (
+	Header_timeout = "Gogroup-Timeout" // remaining deadline in milliseconds
+	Header_id      = "Gogroup-Id"      // Id() of the Group
+)
+
+// Id returns a random id of the Group, or the id received by
+// Request_group(), so the Groups of a call chain across services share one
+// id, e.g. for logs.
+//
+func (o *Group) Id() string {
+	o.wait_lock.Lock()
+	defer o.wait_lock.Unlock()
+	if o.id == "" {
+		b := make([]byte, 8)
+		rand.Read(b)
+		o.id = hex.EncodeToString(b)
+	}
+	return o.id
+}
+
+// Set_headers sets Header_id, and Header_timeout when the Group has a
+// deadline, in h of an outgoing request, so the server can use
+// Request_group() to stay within the remaining budget.
+//
+func (o *Group) Set_headers(h http.Header) {
+	h.Set(Header_id, o.Id())
+	if d, ok := o.Deadline(); ok {
+		ms := d.Sub(o.now()).Milliseconds()
+		if ms < 0 {
+			ms = 0
+		}
+		h.Set(Header_timeout, strconv.FormatInt(ms, 10))
+	}
+}
+
+// Transport returns an http.RoundTripper calling base, or
+// http.DefaultTransport when base is nil, with Set_headers() of the Group of
+// each request Context, see From_context().
+//
+func Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return round_tripper(func(r *http.Request) (*http.Response, error) {
+		if g, ok := From_context(r.Context()); ok {
+			r = r.Clone(r.Context())
+			g.Set_headers(r.Header)
+		}
+		return base.RoundTrip(r)
+	})
+}
+
+type round_tripper func(r *http.Request) (*http.Response, error)
+
+func (o round_tripper) RoundTrip(r *http.Request) (*http.Response, error) { return o(r) }
+
+// Request_group returns a With_cancel() child Group for the request r, with
+// the Header_timeout deadline and the Header_id id of the client, when
+// present; an id longer than 64 bytes is ignored. The child is also canceled
+// when the request Context is, e.g. when the client goes away. opt are added
+// to the child options.
+//
+func (o *Group) Request_group(r *http.Request, opt ...option) *Group {
+	ctx := With_cancel(o)
+	if ms, err := strconv.ParseInt(r.Header.Get(Header_timeout), 10, 64); err == nil {
+		ctx = With_timeout(o, time.Duration(ms)*time.Millisecond)
+	}
+	id := r.Header.Get(Header_id)
+	if 64 < len(id) {
+		id = ""
+	}
+	g := New(append([]option{ctx, func(o *Group) { o.id = id }}, opt...)...)
+	stop := context.AfterFunc(r.Context(), g.Cancel)
+	context.AfterFunc(g, func() { stop() })
+	return g
+}
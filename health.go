@@ -0,0 +1,121 @@
+package gogroup
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+)
+
+// Probe_kind is the kind of a health probe.
+//
+type Probe_kind int
+
+const (
+	Probe_live  Probe_kind = iota // liveness, restart when failing
+	Probe_ready                   // readiness, no traffic when failing
+)
+
+func (o Probe_kind) String() string {
+	switch o {
+	case Probe_live:
+		return "live"
+	case Probe_ready:
+		return "ready"
+	}
+	return "unknown"
+}
+
+type probe struct {
+	kind Probe_kind
+	f    func() error
+}
+
+// Check is the result of one probe in a Health_report.
+//
+type Check struct {
+	Name string
+	Kind string
+	Err  string `json:",omitempty"`
+}
+
+// Health_report is returned by Health().
+//
+type Health_report struct {
+	Live   bool
+	Ready  bool
+	Checks []Check
+}
+
+// Probe adds a health probe named name. f returns a non-nil error when the
+// task it checks is unhealthy. The returned func removes the probe, e.g. when
+// the task ends.
+//
+func (o *Group) Probe(name string, kind Probe_kind, f func() error) (remove func()) {
+	o.wait_lock.Lock()
+	defer o.wait_lock.Unlock()
+	if o.probes == nil {
+		o.probes = map[string]*probe{}
+	}
+	p := &probe{kind: kind, f: f}
+	o.probes[name] = p
+	return func() {
+		o.wait_lock.Lock()
+		defer o.wait_lock.Unlock()
+		if o.probes[name] == p {
+			delete(o.probes, name)
+		}
+	}
+}
+
+// Health calls the probes. The Group is not ready once it is canceled.
+//
+func (o *Group) Health() (r Health_report) {
+	o.wait_lock.Lock()
+	names := make([]string, 0, len(o.probes))
+	probes := make(map[string]*probe, len(o.probes))
+	for name, p := range o.probes {
+		names = append(names, name)
+		probes[name] = p
+	}
+	o.wait_lock.Unlock()
+	sort.Strings(names)
+	r.Live, r.Ready = true, true
+	if err := o.Err(); err != nil {
+		r.Ready = false
+		r.Checks = append(r.Checks, Check{Name: "group", Kind: Probe_ready.String(), Err: err.Error()})
+	}
+	for _, name := range names {
+		p := probes[name]
+		c := Check{Name: name, Kind: p.kind.String()}
+		if err := p.f(); err != nil {
+			c.Err = err.Error()
+			switch p.kind {
+			case Probe_live:
+				r.Live = false
+			case Probe_ready:
+				r.Ready = false
+			}
+		}
+		r.Checks = append(r.Checks, c)
+	}
+	return
+}
+
+// Health_handler returns an http.Handler for a kind of probe, e.g. a
+// Kubernetes livenessProbe or readinessProbe. It writes the Health_report as
+// JSON with status 200, or 503 when the kind is failing.
+//
+func (o *Group) Health_handler(kind Probe_kind) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h := o.Health()
+		ok := h.Live
+		if kind == Probe_ready {
+			ok = h.Ready
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if !ok {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(h)
+	})
+}
@@ -0,0 +1,60 @@
+package gogroup
+
+import (
+	"context"
+	"runtime/debug"
+	"time"
+)
+
+// Hedge calls f as a task of g. If f has not returned after delay a second
+// call of f is started. The first successful result is returned and the
+// context of the other call is canceled. An error before delay is returned
+// without a second call; after delay the first error is returned when both
+// calls fail. The calls do not cancel g and their errors are not passed to
+// Set_err(). Calls and second calls are counted in Stats().Hedge_calls and
+// Stats().Hedges. A panic in f is the error of its call as a *Panic_error,
+// then goes on to With_recover().
+//
+func Hedge[T any](g *Group, delay time.Duration, f func(ctx context.Context) (T, error)) (T, error) {
+	ctx, cancel := context.WithCancel(g)
+	defer cancel()
+	results := make(chan Result[T], 2)
+	attempt := func() {
+		if err := g.spawn("hedge", false, func() error {
+			defer func() {
+				if p := recover(); p != nil {
+					results <- Result[T]{Err: &Panic_error{Value: p, Stack: debug.Stack()}}
+					panic(p)
+				}
+			}()
+			v, err := f(ctx)
+			results <- Result[T]{Val: v, Err: err}
+			return nil
+		}); err != nil {
+			results <- Result[T]{Err: err}
+		}
+	}
+	g.wait_lock.Lock()
+	g.hedge_calls++
+	g.wait_lock.Unlock()
+	attempt()
+	t := time.NewTimer(delay)
+	defer t.Stop()
+	select {
+	case r := <-results:
+		return r.Val, r.Err
+	case <-t.C:
+	}
+	g.wait_lock.Lock()
+	g.hedges++
+	g.wait_lock.Unlock()
+	attempt()
+	r := <-results
+	if r.Err == nil {
+		return r.Val, nil
+	}
+	if r2 := <-results; r2.Err == nil {
+		return r2.Val, nil
+	}
+	return r.Val, r.Err
+}
@@ -0,0 +1,115 @@
+package gogroup
+
+import "context"
+
+// With_on_start() calls f from New(), before New() returns and so before any
+// task is started, e.g. for warmup. With_on_start() may be used more than
+// once; the funcs are called in order. An error is passed to Set_err(),
+// cancels the Group and skips the remaining funcs.
+//
+func With_on_start(f func(ctx context.Context) error) option {
+	return func(o *Group) {
+		o.on_start = append(o.on_start, f)
+	}
+}
+
+func (o *Group) run_on_start() {
+	for _, f := range o.on_start {
+		if err := call(o, f); err != nil {
+			o.Set_err(err)
+			o.Cancel()
+			return
+		}
+	}
+	o.on_start = nil
+}
+
+// Pre_stop calls f when the Group is canceled, while tasks drain and before
+// Wait() returns, e.g. for teardown. The funcs are called in order with a
+// context that has the Group values but is not canceled. An error is passed
+// to Set_err(). A func added after the Group is canceled may not be called.
+//
+func (o *Group) Pre_stop(f func(ctx context.Context) error) {
+	o.wait_lock.Lock()
+	defer o.wait_lock.Unlock()
+	if len(o.pre_stop) == 0 {
+		if !o.hold() {
+			return
+		}
+		go o.run_pre_stop()
+	}
+	o.pre_stop = append(o.pre_stop, f)
+}
+
+func (o *Group) run_pre_stop() {
+	defer o.wg().Done()
+	<-o.Done()
+	ctx := context.WithoutCancel(o)
+	o.wait_lock.Lock()
+	hooks := o.pre_stop
+	o.wait_lock.Unlock()
+	for _, f := range hooks {
+		if err := call(ctx, f); err != nil {
+			o.Set_err(err)
+		}
+	}
+}
+
+// On_done calls f once with the Wait() error after all tasks have ended and
+// before Wait() returns, e.g. to flush metrics or write a final status line.
+// The funcs are called in order.
+//
+func (o *Group) On_done(f func(err error)) {
+	o.wait_lock.Lock()
+	defer o.wait_lock.Unlock()
+	o.on_done = append(o.on_done, f)
+}
+
+func (o *Group) run_on_done(err error) {
+	o.wait_lock.Lock()
+	hooks := o.on_done
+	o.wait_lock.Unlock()
+	for _, f := range hooks {
+		f(err)
+	}
+}
+
+// On_cancel calls f with the context.Cause() of the Group when it is
+// canceled, by a signal, deadline, task end or Cancel(), while tasks may
+// still be running, so a component can start draining. f is called at once
+// if the Group is already canceled, before On_cancel returns if the Group
+// has ended. Wait() waits for f to return.
+//
+func (o *Group) On_cancel(f func(cause error)) {
+	o.wait_lock.Lock()
+	ok := o.hold()
+	o.wait_lock.Unlock()
+	if !ok {
+		f(context.Cause(o))
+		return
+	}
+	context.AfterFunc(o, func() {
+		defer o.wg().Done()
+		f(context.Cause(o))
+	})
+}
+
+// Defer adds f to the funcs called in last in, first out order after all
+// tasks have ended and before Wait() returns, e.g. to close files or pools
+// used by the tasks. They are called before the On_done() funcs.
+//
+func (o *Group) Defer(f func()) {
+	o.wait_lock.Lock()
+	defer o.wait_lock.Unlock()
+	o.defers = append(o.defers, f)
+}
+
+func (o *Group) run_defers() {
+	o.wait_lock.Lock()
+	defers := o.defers
+	o.defers = nil
+	o.wait_lock.Unlock()
+	for i := len(defers) - 1; 0 <= i; i-- {
+		defers[i]()
+	}
+}
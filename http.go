@@ -0,0 +1,112 @@
+package gogroup
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Go_http serves srv on l, or srv.Addr when l is nil, as a Go() task until
+// the Group is canceled. srv.Shutdown() is then given grace to finish
+// active requests before srv.Close(). Serve and Shutdown errors are passed
+// to Set_err().
+//
+func (o *Group) Go_http(srv *http.Server, l net.Listener, grace time.Duration) {
+	name := "http " + srv.Addr
+	if l != nil {
+		name = "http " + l.Addr().String()
+	}
+	o.Go_name(name, func() error {
+		serve := make(chan error, 1)
+		go func() {
+			if l == nil {
+				serve <- srv.ListenAndServe()
+			} else {
+				serve <- srv.Serve(l)
+			}
+		}()
+		select {
+		case err := <-serve:
+			if errors.Is(err, http.ErrServerClosed) {
+				return nil
+			}
+			return err
+		case <-o.Done():
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), grace)
+		defer cancel()
+		err := srv.Shutdown(ctx)
+		if err != nil {
+			srv.Close()
+		}
+		if serr := <-serve; err == nil && !errors.Is(serr, http.ErrServerClosed) {
+			err = serr
+		}
+		return err
+	})
+}
+
+// Middleware registers each request with g as a task that does not cancel g
+// when it ends, so Wait() drains in-flight requests. The request Context is
+// not canceled by g, so in-flight requests finish within the Go_http() grace,
+// and From_context() of it returns g, so a handler can start tasks. Requests
+// are answered 503 Service Unavailable after Drain() or once g is canceled.
+//
+func Middleware(g *Group) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := g.Err(); err != nil {
+				http.Error(w, err.Error(), http.StatusServiceUnavailable)
+				return
+			}
+			index, err := g.Register_request("http " + r.Method + " " + r.URL.Path)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusServiceUnavailable)
+				return
+			}
+			defer g.Unregister(index)
+			ctx := context.WithValue(r.Context(), group_key{}, g)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// Http_context sets srv.BaseContext so the Context of every request is
+// derived from the Group, and From_context() returns the Group. Call it
+// before srv serves, e.g. before Go_http(). With cancel the request Contexts
+// are also canceled when the Group is canceled, instead of finishing within
+// the Go_http() grace; a ConnContext already set is still called, and the
+// Group cancelation is added to its Context until the connection is closed.
+//
+func (o *Group) Http_context(srv *http.Server, cancel bool) {
+	if !cancel {
+		base := context.WithoutCancel(o)
+		srv.BaseContext = func(net.Listener) context.Context { return base }
+		return
+	}
+	srv.BaseContext = func(net.Listener) context.Context { return o }
+	conn_context := srv.ConnContext
+	if conn_context == nil {
+		return
+	}
+	var stops sync.Map // net.Conn: context.CancelFunc
+	srv.ConnContext = func(ctx context.Context, c net.Conn) context.Context {
+		ctx, cancel := o.Bind(conn_context(ctx, c))
+		stops.Store(c, cancel)
+		return ctx
+	}
+	conn_state := srv.ConnState
+	srv.ConnState = func(c net.Conn, state http.ConnState) {
+		if state == http.StateClosed || state == http.StateHijacked {
+			if stop, ok := stops.LoadAndDelete(c); ok {
+				stop.(context.CancelFunc)()
+			}
+		}
+		if conn_state != nil {
+			conn_state(c, state)
+		}
+	}
+}
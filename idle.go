@@ -0,0 +1,49 @@
+package gogroup
+
+import "time"
+
+// With_idle_timeout() cancels the Group when no task has been registered for
+// d, so a daemon with a dynamic workload can shut down or be restarted when
+// idle. The idle time starts at New() and again each time the last running
+// task ends.
+//
+func With_idle_timeout(d time.Duration) option {
+	return func(o *Group) {
+		o.idle = d
+	}
+}
+
+// idle_start starts the idle timer. wait_lock must be held.
+//
+func (o *Group) idle_start() {
+	if o.idle <= 0 || 0 < o.wait_register.len() {
+		return
+	}
+	o.idle_since = o.now()
+	if o.idle_timer == nil {
+		o.idle_timer = o.after_func(o.idle, o.idle_expire)
+		return
+	}
+	o.idle_timer.Reset(o.idle)
+}
+
+// idle_stop stops the idle timer. wait_lock must be held.
+//
+func (o *Group) idle_stop() {
+	if o.idle_timer != nil {
+		o.idle_timer.Stop()
+	}
+}
+
+func (o *Group) idle_expire() {
+	o.wait_lock.Lock()
+	idle := o.wait_register.len() == 0 && o.idle <= o.now().Sub(o.idle_since)
+	o.wait_lock.Unlock()
+	if !idle || o.Err() != nil {
+		return
+	}
+	if o.log != nil {
+		o.log.Info("idle timeout", "idle", o.idle)
+	}
+	o.Cancel()
+}
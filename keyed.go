@@ -0,0 +1,56 @@
+package gogroup
+
+import "context"
+
+// Go_keyed runs f as a task. Tasks with the same key run one at a time in
+// the order of the Go_keyed() calls; tasks with different keys run
+// concurrently. Unlike Go(), a task ending without an error does not cancel
+// the Group. An error is passed to Set_err() and cancels the Group. Tasks
+// still queued for a key are dropped after an error, a panic or
+// cancelation.
+//
+func (o *Group) Go_keyed(key string, f func(ctx context.Context) error) {
+	o.wait_lock.Lock()
+	if q, ok := o.keyed[key]; ok {
+		o.keyed[key] = append(q, f)
+		o.wait_lock.Unlock()
+		return
+	}
+	if o.keyed == nil {
+		o.keyed = map[string][]func(ctx context.Context) error{}
+	}
+	o.keyed[key] = nil
+	o.wait_lock.Unlock()
+	err := o.spawn(key, false, func() error {
+		defer func() {
+			if p := recover(); p != nil {
+				o.wait_lock.Lock()
+				delete(o.keyed, key)
+				o.wait_lock.Unlock()
+				panic(p)
+			}
+		}()
+		for {
+			if err := f(o); err != nil || o.Err() != nil {
+				o.wait_lock.Lock()
+				delete(o.keyed, key)
+				o.wait_lock.Unlock()
+				return err
+			}
+			o.wait_lock.Lock()
+			q := o.keyed[key]
+			if len(q) == 0 {
+				delete(o.keyed, key)
+				o.wait_lock.Unlock()
+				return nil
+			}
+			f, o.keyed[key] = q[0], q[1:]
+			o.wait_lock.Unlock()
+		}
+	})
+	if err != nil {
+		o.wait_lock.Lock()
+		delete(o.keyed, key)
+		o.wait_lock.Unlock()
+	}
+}
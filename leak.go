@@ -0,0 +1,37 @@
+package gogroup
+
+import (
+	"sort"
+	"time"
+)
+
+// Leak is a task still registered after the With_leak_report() grace period.
+//
+type Leak struct {
+	Task_info
+	Stack []byte // stack of the Register()/Go() caller
+}
+
+// With_leak_report() calls f with the tasks that have not called
+// Unregister() d after the Group is canceled. f is not called when all
+// tasks have ended. The stack of each Register()/Go() call is captured for
+// the report.
+//
+func With_leak_report(d time.Duration, f func([]Leak)) option {
+	return func(o *Group) {
+		o.stacks = true
+		o.graces = append(o.graces, grace{d: d, f: func() {
+			o.wait_lock.Lock()
+			leaks := make([]Leak, 0, o.wait_register.len())
+			o.wait_register.each(func(index int, t *task) {
+				leaks = append(leaks, Leak{Task_info: t.info(index), Stack: t.stack})
+			})
+			o.wait_lock.Unlock()
+			if len(leaks) == 0 {
+				return
+			}
+			sort.Slice(leaks, func(i, j int) bool { return task_before(leaks[i].Task_info, leaks[j].Task_info) })
+			f(leaks)
+		}})
+	}
+}
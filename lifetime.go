@@ -0,0 +1,57 @@
+package gogroup
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Err_max_lifetime is the context.Cause() of a Group canceled by
+// With_max_lifetime().
+//
+var Err_max_lifetime = errors.New("gogroup: max lifetime exceeded")
+
+// With_max_lifetime() cancels the Group d after New() regardless of task
+// activity, a guardrail against runaway jobs. context.Cause() of the Group
+// is Err_max_lifetime.
+//
+func With_max_lifetime(d time.Duration) option {
+	return func(o *Group) {
+		o.lifetime = d
+	}
+}
+
+func (o *Group) start_lifetime() {
+	if o.lifetime <= 0 {
+		return
+	}
+	t := o.after_func(o.lifetime, func() {
+		if o.Err() != nil {
+			return
+		}
+		if o.log != nil {
+			o.log.Info("max lifetime exceeded", "lifetime", o.lifetime)
+		}
+		o.cancel(Err_max_lifetime)
+	})
+	context.AfterFunc(o, func() { t.Stop() })
+}
+
+// with_cause wraps the Group Context so cancel() can record a cause.
+//
+func (o *Group) with_cause() {
+	var cancel context.CancelFunc
+	o.Context, o.cancel_cause = context.WithCancelCause(o.Context)
+	cancel, o.CancelFunc = o.CancelFunc, func() {
+		o.cancel_cause(nil)
+		cancel()
+	}
+}
+
+// cancel cancels the Group with cause, which is kept if the Group is
+// already canceled.
+//
+func (o *Group) cancel(cause error) {
+	o.cancel_cause(cause)
+	o.Cancel()
+}
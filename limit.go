@@ -0,0 +1,74 @@
+package gogroup
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Limiter delays task starts. *golang.org/x/time/rate.Limiter is a Limiter.
+//
+type Limiter interface {
+	Wait(ctx context.Context) error
+}
+
+// With_rate_limit() waits on l before each task started with Go(),
+// Go_name(), and the helpers built on them runs. The task is registered
+// immediately; only the call of its func is delayed. A task whose wait is
+// ended by the Group cancelation is not run.
+//
+func With_rate_limit(l Limiter) option {
+	return func(o *Group) {
+		o.limiter = l
+	}
+}
+
+// New_limiter returns a token bucket Limiter allowing per_second starts with
+// bursts of burst. A per_second <= 0 is unlimited.
+//
+func New_limiter(per_second float64, burst int) Limiter {
+	if per_second <= 0 {
+		return unlimited{}
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &bucket{rate: per_second, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+type unlimited struct{}
+
+func (unlimited) Wait(ctx context.Context) error {
+	return nil
+}
+
+type bucket struct {
+	lock   sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func (o *bucket) Wait(ctx context.Context) error {
+	o.lock.Lock()
+	now := time.Now()
+	o.tokens += now.Sub(o.last).Seconds() * o.rate
+	if o.burst < o.tokens {
+		o.tokens = o.burst
+	}
+	o.last = now
+	o.tokens--
+	var d time.Duration
+	if o.tokens < 0 {
+		d = time.Duration(-o.tokens / o.rate * float64(time.Second))
+	}
+	o.lock.Unlock()
+	if d == 0 || sleep(ctx, d) {
+		return nil
+	}
+	o.lock.Lock()
+	o.tokens++
+	o.lock.Unlock()
+	return ctx.Err()
+}
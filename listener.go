@@ -0,0 +1,87 @@
+package gogroup
+
+import (
+	"context"
+	"net"
+	"sync"
+)
+
+type listener_option func(o *listener)
+
+type listener struct {
+	drain bool
+	lock  sync.Mutex
+	conns map[net.Conn]struct{}
+}
+
+// With_conn_drain() makes Go_listener() let connection handlers finish after
+// the Group is canceled, instead of closing their connections.
+//
+func With_conn_drain() listener_option {
+	return func(o *listener) {
+		o.drain = true
+	}
+}
+
+// Go_listener accepts connections on l as a Go() task until the Group is
+// canceled, then closes l. handle is called with the Group and each
+// connection in a task that ends without canceling the Group; the
+// connection is closed when handle returns. An Accept() error is passed to
+// Set_err(). Connections are closed on cancel unless With_conn_drain() is
+// used.
+//
+func (o *Group) Go_listener(l net.Listener, handle func(ctx context.Context, c net.Conn), opt ...listener_option) {
+	ln := &listener{conns: map[net.Conn]struct{}{}}
+	for _, f := range opt {
+		f(ln)
+	}
+	name := "listener " + l.Addr().String()
+	o.Go_name(name, func() error {
+		stop := context.AfterFunc(o, func() {
+			l.Close()
+			if !ln.drain {
+				ln.close()
+			}
+		})
+		defer stop()
+		for {
+			c, err := l.Accept()
+			if err != nil {
+				if o.Err() != nil {
+					return nil
+				}
+				return err
+			}
+			ln.add(c)
+			if err := o.spawn("conn "+c.RemoteAddr().String(), false, func() error {
+				defer ln.remove(c)
+				handle(o, c)
+				return nil
+			}); err != nil {
+				ln.remove(c)
+				return nil
+			}
+		}
+	})
+}
+
+func (o *listener) add(c net.Conn) {
+	o.lock.Lock()
+	o.conns[c] = struct{}{}
+	o.lock.Unlock()
+}
+
+func (o *listener) remove(c net.Conn) {
+	o.lock.Lock()
+	delete(o.conns, c)
+	o.lock.Unlock()
+	c.Close()
+}
+
+func (o *listener) close() {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	for c := range o.conns {
+		c.Close()
+	}
+}
@@ -0,0 +1,14 @@
+package gogroup
+
+import "log/slog"
+
+// With_logger() logs the Group lifecycle to l: task start/done with duration
+// and error, signal receipt, and cancelation with the cause. Task start/done
+// are logged at slog.LevelDebug; failed tasks at slog.LevelError. The signal
+// line ending written to os.Stderr is replaced by the log entry.
+//
+func With_logger(l *slog.Logger) option {
+	return func(o *Group) {
+		o.log = l
+	}
+}
@@ -0,0 +1,81 @@
+package gogroup
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+type loop_option func(o *loop)
+
+type loop struct {
+	backoff Backoff
+	fatal   func(error) bool
+}
+
+// With_loop_backoff() sets the delay before f is called again after an
+// error. The default is Exponential(100ms, 30s, 0). The loop ends with the
+// error when b stops retrying.
+//
+func With_loop_backoff(b Backoff) loop_option {
+	return func(o *loop) {
+		o.backoff = b
+	}
+}
+
+// With_fatal() ends the loop with an error when fatal(err) is true.
+// Permanent() errors are always fatal.
+//
+func With_fatal(fatal func(error) bool) loop_option {
+	return func(o *loop) {
+		o.fatal = fatal
+	}
+}
+
+// Go_loop calls f as a Go_name() task, calling it again whenever it returns,
+// for consumer loops of a message queue. f returning nil is called again at
+// once, so f should block waiting for work. After an error, or a panic, f is
+// called again after a backoff that starts over when f returns nil. The
+// task ends only when the Group is canceled, or with a fatal error, which is
+// passed to Set_err(). Restarts after errors are counted in
+// Stats().Restarts.
+//
+func (o *Group) Go_loop(name string, f func(ctx context.Context) error, opt ...loop_option) {
+	l := &loop{backoff: Exponential(100*time.Millisecond, 30*time.Second, 0)}
+	for _, fn := range opt {
+		fn(l)
+	}
+	o.Go_name(name, func() error {
+		for n := 0; o.Err() == nil; {
+			err := call(o, f)
+			if err == nil {
+				n = 0
+				continue
+			}
+			var p *permanent
+			switch {
+			case errors.As(err, &p):
+				return p.err
+			case l.fatal != nil && l.fatal(err):
+				return err
+			case o.Err() != nil:
+				return nil
+			}
+			n++
+			o.wait_lock.Lock()
+			o.restarts++
+			o.wait_lock.Unlock()
+			if o.log != nil {
+				o.log.Warn("loop restart", "name", name, "restarts", n, "err", err)
+			}
+			d, ok := l.backoff.Next(n, err)
+			if !ok {
+				return err
+			}
+			if !sleep(o, d) {
+				return nil
+			}
+		}
+		return nil
+	})
+}
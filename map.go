@@ -0,0 +1,103 @@
+package gogroup
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// Map calls f for each element of in, with at most limit calls running at
+// once, and returns the results in the order of in. limit <= 0 is len(in).
+// The calls run as tasks of g that do not cancel g when they end without an
+// error. The first error is passed to Set_err(), cancels g, and is returned.
+// Elements not started when g is canceled are skipped and the context error
+// is returned.
+//
+func Map[S, T any](g *Group, in []S, limit int, f func(ctx context.Context, s S) (T, error)) ([]T, error) {
+	r := make([]T, len(in))
+	if limit <= 0 || len(in) < limit {
+		limit = len(in)
+	}
+	var (
+		next     atomic.Int64
+		wg       sync.WaitGroup
+		err_once sync.Once
+		err      error
+	)
+	wg.Add(limit)
+	for i := 0; i < limit; i++ {
+		e := g.spawn("map", false, func() error {
+			defer wg.Done()
+			for g.Err() == nil {
+				i := int(next.Add(1) - 1)
+				if len(in) <= i {
+					return nil
+				}
+				v, e := f(g, in[i])
+				if e != nil {
+					err_once.Do(func() { err = e })
+					return e
+				}
+				r[i] = v
+			}
+			return nil
+		})
+		if e != nil {
+			err_once.Do(func() { err = e })
+			wg.Done()
+		}
+	}
+	wg.Wait()
+	if err == nil && int(next.Load()) < len(in) {
+		err = g.Err()
+	}
+	return r, err
+}
+
+// For_each calls f for each value received from in, with at most limit calls
+// running at once, until in is closed or g is canceled. limit <= 0 is 1. The
+// calls run as tasks of g that do not cancel g when they end without an
+// error. The first error is passed to Set_err(), cancels g, and is returned.
+// The context error is returned when g is canceled before in is closed.
+//
+func For_each[T any](g *Group, in <-chan T, limit int, f func(ctx context.Context, v T) error) error {
+	if limit <= 0 {
+		limit = 1
+	}
+	var (
+		wg       sync.WaitGroup
+		err_once sync.Once
+		err      error
+		closed   atomic.Bool
+	)
+	wg.Add(limit)
+	for i := 0; i < limit; i++ {
+		e := g.spawn("for_each", false, func() error {
+			defer wg.Done()
+			for {
+				select {
+				case <-g.Done():
+					return nil
+				case v, ok := <-in:
+					if !ok {
+						closed.Store(true)
+						return nil
+					}
+					if e := f(g, v); e != nil {
+						err_once.Do(func() { err = e })
+						return e
+					}
+				}
+			}
+		})
+		if e != nil {
+			err_once.Do(func() { err = e })
+			wg.Done()
+		}
+	}
+	wg.Wait()
+	if err == nil && !closed.Load() {
+		err = g.Err()
+	}
+	return err
+}
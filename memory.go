@@ -0,0 +1,74 @@
+package gogroup
+
+import (
+	"errors"
+	"math"
+	"runtime/debug"
+	"runtime/metrics"
+	"time"
+)
+
+// Err_memory_pressure is the context.Cause() of a Group canceled by
+// With_memory_pressure().
+//
+var Err_memory_pressure = errors.New("gogroup: memory pressure")
+
+type memory_monitor struct {
+	fraction float64
+	interval time.Duration
+	hook     func(used, limit uint64)
+}
+
+// With_memory_pressure() checks the memory used by the Go runtime every
+// interval. When it is more than fraction of the memory limit, GOMEMLIMIT or
+// debug.SetMemoryLimit(), f is called, e.g. to shed tasks. With a nil f the
+// Group is canceled with the Err_memory_pressure cause. Nothing is checked
+// when no memory limit is set.
+//
+func With_memory_pressure(fraction float64, interval time.Duration, f func(used, limit uint64)) option {
+	return func(o *Group) {
+		o.memory = &memory_monitor{fraction: fraction, interval: interval, hook: f}
+	}
+}
+
+func (o *Group) start_memory_monitor() {
+	m := o.memory
+	if m == nil || m.interval <= 0 {
+		return
+	}
+	o.wg().Add(1)
+	go func() {
+		defer o.wg().Done()
+		samples := []metrics.Sample{
+			{Name: "/memory/classes/total:bytes"},
+			{Name: "/memory/classes/heap/released:bytes"},
+		}
+		t := time.NewTicker(m.interval)
+		defer t.Stop()
+		for {
+			select {
+			case <-o.Done():
+				return
+			case <-t.C:
+			}
+			limit := debug.SetMemoryLimit(-1)
+			if limit <= 0 || limit == math.MaxInt64 {
+				continue
+			}
+			metrics.Read(samples)
+			used := samples[0].Value.Uint64() - samples[1].Value.Uint64()
+			if float64(used) <= m.fraction*float64(limit) {
+				continue
+			}
+			if o.log != nil {
+				o.log.Warn("memory pressure", "used", used, "limit", limit)
+			}
+			if m.hook != nil {
+				m.hook(used, uint64(limit))
+				continue
+			}
+			o.cancel(Err_memory_pressure)
+			return
+		}
+	}()
+}
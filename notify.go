@@ -0,0 +1,45 @@
+package gogroup
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// Use With_notify_context() as the context to New(). The Group context is
+// derived from os/signal.NotifyContext(ctx, signals...), so the signal
+// lifecycle is tied to the context and composes with other NotifyContext
+// users, instead of a dedicated signal.Notify(). signals defaults to
+// os.Interrupt and syscall.SIGTERM. Group.Interrupted is set when a signal
+// cancels the Group.
+//
+// parent goroutine will not wait on the child.
+// parent.Cancel() will call child.Cancel().
+// child.Cancel() will not call parent.Cancel().
+//
+func With_notify_context(ctx context.Context, signals ...os.Signal) option {
+	return func(o *Group) {
+		switch {
+		case o.Context != nil:
+			panic("context already set")
+		case ctx == nil:
+			ctx = context.Background()
+		}
+		if len(signals) == 0 {
+			signals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+		}
+		// with_notify() derives the Group context, after With_synctest().
+		o.notify_parent, o.signals = ctx, signals
+		o.Context = ctx
+	}
+}
+
+func (o *Group) with_notify() {
+	if o.no_signal {
+		o.notify, o.notify_stop = context.WithCancel(o.notify_parent)
+	} else {
+		o.notify, o.notify_stop = signal.NotifyContext(o.notify_parent, o.signals...)
+	}
+	o.Context, o.CancelFunc = context.WithCancel(o.notify)
+}
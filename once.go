@@ -0,0 +1,68 @@
+package gogroup
+
+import (
+	"context"
+	"runtime/debug"
+)
+
+// Result is the value and error of a task returning a value.
+//
+type Result[T any] struct {
+	Val    T
+	Err    error
+	Shared bool // the result was delivered to more than one caller
+}
+
+type once_call struct {
+	waiters []func(v any, err error, shared bool)
+}
+
+// Go_once runs f as a task unless a task with the same key is running, in
+// which case the caller shares the running task's result. The returned
+// channel receives the Result and is closed. Unlike Go(), a task ending
+// without an error does not cancel the Group. An error is passed to Set_err()
+// and cancels the Group. A panic in f is delivered to the callers as a
+// *Panic_error, then goes on to With_recover().
+//
+func Go_once[T any](g *Group, key string, f func(ctx context.Context) (T, error)) <-chan Result[T] {
+	ch := make(chan Result[T], 1)
+	waiter := func(v any, err error, shared bool) {
+		val, _ := v.(T)
+		ch <- Result[T]{Val: val, Err: err, Shared: shared}
+		close(ch)
+	}
+	g.wait_lock.Lock()
+	if c, ok := g.once[key]; ok {
+		c.waiters = append(c.waiters, waiter)
+		g.wait_lock.Unlock()
+		return ch
+	}
+	if g.once == nil {
+		g.once = map[string]*once_call{}
+	}
+	c := &once_call{waiters: []func(any, error, bool){waiter}}
+	g.once[key] = c
+	g.wait_lock.Unlock()
+	done := func(v any, err error) {
+		g.wait_lock.Lock()
+		delete(g.once, key)
+		g.wait_lock.Unlock()
+		for _, w := range c.waiters {
+			w(v, err, 1 < len(c.waiters))
+		}
+	}
+	if err := g.spawn(key, false, func() error {
+		defer func() {
+			if p := recover(); p != nil {
+				done(nil, &Panic_error{Value: p, Stack: debug.Stack()})
+				panic(p)
+			}
+		}()
+		v, err := f(g)
+		done(v, err)
+		return err
+	}); err != nil {
+		done(nil, err)
+	}
+	return ch
+}
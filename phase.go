@@ -0,0 +1,73 @@
+package gogroup
+
+import "context"
+
+// Phase returns the child Group for the shutdown phase name, creating it on
+// first use. When the Group is canceled, phases are canceled in the reverse
+// order of their creation, each draining before the next is canceled, so
+// create "storage" before "workers" before "ingress". A phase canceled on its
+// own, e.g. when a task returns, cancels the Group. The error of a phase is
+// passed to Set_err() when it has drained. Wait() waits for all phases.
+//
+func (o *Group) Phase(name string) *Group {
+	o.phase_lock.Lock()
+	defer o.phase_lock.Unlock()
+	o.wait_lock.Lock()
+	for _, p := range o.phases {
+		if p.name == name {
+			o.wait_lock.Unlock()
+			return p
+		}
+	}
+	first := len(o.phases) == 0
+	o.wait_lock.Unlock()
+	// The Group handles signals and cancels the phases in order.
+	p := New(With_values_only(o), With_joined_child(), With_name(name), without_signals())
+	context.AfterFunc(p, o.Cancel)
+	if o.Err() != nil {
+		p.Cancel()
+		return p
+	}
+	o.wait_lock.Lock()
+	defer o.wait_lock.Unlock()
+	if first {
+		if !o.hold() {
+			p.Cancel()
+			return p
+		}
+		go o.stop_phases()
+	}
+	o.phases = append(o.phases, p)
+	return p
+}
+
+func without_signals() option {
+	return func(o *Group) {
+		o.no_signal = true
+	}
+}
+
+func (o *Group) stop_phases() {
+	defer o.wg().Done()
+	<-o.Done()
+	for {
+		o.wait_lock.Lock()
+		var p *Group
+		if n := len(o.phases); 0 < n {
+			p = o.phases[n-1]
+			o.phases = o.phases[:n-1]
+		}
+		o.wait_lock.Unlock()
+		if p == nil {
+			return
+		}
+		if o.log != nil {
+			o.log.Info("phase stop", "phase", p.name)
+		}
+		p.Cancel()
+		<-p.Dead()
+		if err := p.Get_err(); err != nil {
+			o.Set_err(err)
+		}
+	}
+}
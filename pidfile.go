@@ -0,0 +1,62 @@
+package gogroup
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+)
+
+// With_pidfile() writes the process id to path at New(), and removes path
+// when Wait() returns, so init scripts can signal the process. An existing
+// file is replaced. The Group is canceled with the error when path cannot be
+// written.
+//
+func With_pidfile(path string) option {
+	return func(o *Group) {
+		o.pidfile = path
+	}
+}
+
+// With_drain_signal() calls Drain() when sig, e.g. syscall.SIGUSR1, is
+// received, while os.Interrupt and SIGTERM still cancel the Group at once,
+// so init scripts can drive a two-phase shutdown: drain, then terminate
+// after a timeout.
+//
+func With_drain_signal(sig os.Signal) option {
+	return func(o *Group) {
+		o.drain_signal = sig
+	}
+}
+
+func (o *Group) start_pidfile() {
+	if o.pidfile == "" {
+		return
+	}
+	path := o.pidfile
+	if err := os.WriteFile(path, []byte(fmt.Sprintln(os.Getpid())), 0644); err != nil {
+		o.Cancel_err(err)
+		return
+	}
+	o.Defer(func() { os.Remove(path) })
+}
+
+func (o *Group) watch_drain_signal() {
+	if o.drain_signal == nil || o.no_signal {
+		return
+	}
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, o.drain_signal)
+	o.wg().Add(1)
+	go func() {
+		defer o.wg().Done()
+		defer signal.Stop(c)
+		select {
+		case <-c:
+			if o.log != nil {
+				o.log.Info("drain signal", "signal", o.drain_signal)
+			}
+			o.Drain()
+		case <-o.Done():
+		}
+	}()
+}
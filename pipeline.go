@@ -0,0 +1,207 @@
+package gogroup
+
+import (
+	"context"
+	"sync"
+)
+
+// Pipeline connects stages with channels. Each stage runs its workers as
+// tasks of the Group that do not cancel the Group when they end without an
+// error. A stage closes its output channel when its workers end. A stage
+// error is passed to Set_err() and cancels the Group, ending every stage.
+//
+//	p := gogroup.New_pipeline(gg)
+//	nums := gogroup.Source(p, 1, 2, 3)
+//	squares := gogroup.Stage(p, nums, 4, 10, square)
+//	for v := range squares {
+//	}
+//	err := p.Wait()
+//
+type Pipeline struct {
+	g        *Group
+	wg       sync.WaitGroup
+	err_once sync.Once
+	err      error
+}
+
+func New_pipeline(g *Group) *Pipeline {
+	return &Pipeline{g: g}
+}
+
+// Wait blocks until all stages end and returns the first stage error, or the
+// Group context error if the Group was canceled.
+//
+func (o *Pipeline) Wait() error {
+	o.wg.Wait()
+	if o.err != nil {
+		return o.err
+	}
+	return o.g.Err()
+}
+
+func (o *Pipeline) fail(err error) {
+	o.err_once.Do(func() { o.err = err })
+}
+
+// spawn runs f as a stage task and calls done when f returns, or when the
+// Group refuses the task.
+//
+func (o *Pipeline) spawn(name string, f func() error, done func()) {
+	o.wg.Add(1)
+	if err := o.g.spawn(name, false, func() error {
+		defer o.wg.Done()
+		defer done()
+		return f()
+	}); err != nil {
+		o.fail(err)
+		done()
+		o.wg.Done()
+	}
+}
+
+// Source sends vals on the returned channel, then closes it.
+//
+func Source[T any](p *Pipeline, vals ...T) <-chan T {
+	out := make(chan T)
+	p.spawn("source", func() error {
+		for _, v := range vals {
+			select {
+			case out <- v:
+			case <-p.g.Done():
+				return nil
+			}
+		}
+		return nil
+	}, func() { close(out) })
+	return out
+}
+
+// Stage starts workers tasks calling f for each value from in and sending
+// the results on the returned channel, buffered with buffer. Results are not
+// ordered.
+//
+func Stage[I, O any](p *Pipeline, in <-chan I, workers, buffer int, f func(ctx context.Context, v I) (O, error)) <-chan O {
+	if workers < 1 {
+		workers = 1
+	}
+	out := make(chan O, buffer)
+	var stage sync.WaitGroup
+	stage.Add(workers)
+	for i := 0; i < workers; i++ {
+		p.spawn("stage", func() error {
+			for {
+				select {
+				case <-p.g.Done():
+					return nil
+				case v, ok := <-in:
+					if !ok {
+						return nil
+					}
+					r, err := f(p.g, v)
+					if err != nil {
+						p.fail(err)
+						return err
+					}
+					select {
+					case out <- r:
+					case <-p.g.Done():
+						return nil
+					}
+				}
+			}
+		}, stage.Done)
+	}
+	go func() {
+		stage.Wait()
+		close(out)
+	}()
+	return out
+}
+
+type sequenced[T any] struct {
+	seq int
+	v   T
+}
+
+// Ordered_stage is Stage() with results sent in the order of in. At most
+// window values are between being received from in and being sent, bounding
+// the memory used to reorder results. window < workers is workers.
+//
+func Ordered_stage[I, O any](p *Pipeline, in <-chan I, workers, window int, f func(ctx context.Context, v I) (O, error)) <-chan O {
+	if workers < 1 {
+		workers = 1
+	}
+	if window < workers {
+		window = workers
+	}
+	var (
+		out     = make(chan O)
+		jobs    = make(chan sequenced[I])
+		results = make(chan sequenced[O], workers)
+		tokens  = make(chan struct{}, window)
+		stage   sync.WaitGroup
+	)
+	p.spawn("stage", func() error {
+		for seq := 0; ; seq++ {
+			select {
+			case <-p.g.Done():
+				return nil
+			case tokens <- struct{}{}:
+			}
+			select {
+			case <-p.g.Done():
+				return nil
+			case v, ok := <-in:
+				if !ok {
+					return nil
+				}
+				select {
+				case jobs <- sequenced[I]{seq, v}:
+				case <-p.g.Done():
+					return nil
+				}
+			}
+		}
+	}, func() { close(jobs) })
+	stage.Add(workers)
+	for i := 0; i < workers; i++ {
+		p.spawn("stage", func() error {
+			for j := range jobs {
+				r, err := f(p.g, j.v)
+				if err != nil {
+					p.fail(err)
+					return err
+				}
+				select {
+				case results <- sequenced[O]{j.seq, r}:
+				case <-p.g.Done():
+					return nil
+				}
+			}
+			return nil
+		}, stage.Done)
+	}
+	go func() {
+		stage.Wait()
+		close(results)
+	}()
+	p.spawn("stage", func() error {
+		pending := map[int]O{}
+		next := 0
+		for r := range results {
+			pending[r.seq] = r.v
+			for v, ok := pending[next]; ok; v, ok = pending[next] {
+				select {
+				case out <- v:
+				case <-p.g.Done():
+					return nil
+				}
+				delete(pending, next)
+				next++
+				<-tokens
+			}
+		}
+		return nil
+	}, func() { close(out) })
+	return out
+}
@@ -0,0 +1,186 @@
+package gogroup
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+	"time"
+)
+
+var (
+	Err_pool_full   = errors.New("gogroup: pool queue full")
+	Err_pool_closed = errors.New("gogroup: pool closed")
+)
+
+type pool_option func(o *Pool)
+
+// With_pool_queue() sets the Pool queue size. Default is the worker count.
+//
+func With_pool_queue(size int) pool_option {
+	return func(o *Pool) {
+		o.size = size
+	}
+}
+
+// With_pool_nowait() makes Submit() return Err_pool_full instead of blocking
+// when the queue is full.
+//
+func With_pool_nowait() pool_option {
+	return func(o *Pool) {
+		o.nowait = true
+	}
+}
+
+// Pool runs submitted funcs on a fixed number of worker tasks. A panic in a
+// func is recovered and becomes its error. An error is passed to Set_err()
+// and cancels the Group. When the Group is canceled, Submit() returns
+// Err_pool_closed and the workers drain the queue before ending.
+//
+type Pool struct {
+	g       *Group
+	workers int
+	size    int
+	nowait  bool
+	queue   chan func(ctx context.Context) error
+	lock    sync.RWMutex
+	closed  bool
+	stop    chan struct{} // New_procs_pool() worker removal
+}
+
+// New_pool starts workers worker tasks in g.
+//
+func New_pool(g *Group, workers int, opt ...pool_option) *Pool {
+	if workers < 1 {
+		workers = 1
+	}
+	r := &Pool{g: g, workers: workers, size: workers}
+	for _, o := range opt {
+		o(r)
+	}
+	r.queue = make(chan func(ctx context.Context) error, r.size)
+	if g.spawn("pool", false, func() error {
+		<-g.Done()
+		r.lock.Lock()
+		r.closed = true
+		close(r.queue)
+		r.lock.Unlock()
+		return nil
+	}) != nil {
+		r.workers, r.closed = 0, true
+		close(r.queue)
+		return r
+	}
+	for i := 0; i < workers; i++ {
+		if g.spawn("pool", false, r.work) != nil {
+			r.workers = i
+			break
+		}
+	}
+	return r
+}
+
+// procs_interval is how often a New_procs_pool() Pool checks GOMAXPROCS.
+//
+const procs_interval = time.Second
+
+// New_procs_pool is New_pool() with runtime.GOMAXPROCS(0) workers. Workers
+// are added or removed when GOMAXPROCS changes, e.g. with automaxprocs.
+//
+func New_procs_pool(g *Group, opt ...pool_option) *Pool {
+	opt = append(opt[:len(opt):len(opt)], func(o *Pool) {
+		o.stop = make(chan struct{})
+	})
+	r := New_pool(g, runtime.GOMAXPROCS(0), opt...)
+	g.spawn("pool procs", false, func() error {
+		t := time.NewTicker(procs_interval)
+		defer t.Stop()
+		for {
+			select {
+			case <-g.Done():
+				return nil
+			case <-t.C:
+			}
+			n := runtime.GOMAXPROCS(0)
+			for r.Workers() < n {
+				if g.spawn("pool", false, r.work) != nil {
+					return nil
+				}
+				r.add_workers(1)
+			}
+			for n < r.Workers() {
+				select {
+				case r.stop <- struct{}{}:
+					r.add_workers(-1)
+				case <-g.Done():
+					return nil
+				}
+			}
+		}
+	})
+	return r
+}
+
+func (o *Pool) add_workers(n int) {
+	o.lock.Lock()
+	o.workers += n
+	o.lock.Unlock()
+}
+
+// Workers returns the number of worker tasks.
+//
+func (o *Pool) Workers() int {
+	o.lock.RLock()
+	defer o.lock.RUnlock()
+	return o.workers
+}
+
+// Submit queues f. Submit blocks while the queue is full unless
+// With_pool_nowait() is used.
+//
+func (o *Pool) Submit(f func(ctx context.Context) error) error {
+	o.lock.RLock()
+	defer o.lock.RUnlock()
+	if o.closed {
+		return Err_pool_closed
+	}
+	if o.nowait {
+		select {
+		case o.queue <- f:
+			return nil
+		default:
+			return Err_pool_full
+		}
+	}
+	select {
+	case o.queue <- f:
+		return nil
+	case <-o.g.Done():
+		return Err_pool_closed
+	}
+}
+
+// Queued returns the number of funcs waiting for a worker.
+//
+func (o *Pool) Queued() int {
+	return len(o.queue)
+}
+
+func (o *Pool) work() error {
+	for {
+		var f func(ctx context.Context) error
+		var ok bool
+		select {
+		case f, ok = <-o.queue:
+			if !ok {
+				return nil
+			}
+		case <-o.stop:
+			return nil
+		}
+		if err := call(o.g, f); err != nil {
+			o.g.Set_err(err)
+			o.g.Cancel()
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package gogroup
+
+import "context"
+
+type group_key struct{}
+
+// Value returns the Group itself for the package context key, so a Group can
+// be found from contexts derived from it.
+//
+func (o *Group) Value(key interface{}) interface{} {
+	if key == (group_key{}) {
+		return o
+	}
+	return o.Context.Value(key)
+}
+
+// Progress is returned by Group.Progress().
+//
+type Progress struct {
+	Total     int   // tasks registered
+	Completed int   // tasks ended
+	Running   int   // tasks registered and not ended
+	Done      int64 // Report_progress() done units
+	Units     int64 // Report_progress() total units
+}
+
+// Progress returns the task counts and the units reported with
+// Report_progress().
+//
+func (o *Group) Progress() (r Progress) {
+	o.wait_lock.Lock()
+	defer o.wait_lock.Unlock()
+	r.Total = o.wait_index
+	r.Running = o.wait_register.len()
+	r.Completed = r.Total - r.Running
+	r.Done, r.Units = o.done_units, o.total_units
+	return
+}
+
+// Report_progress adds done and total units of work to the Group ctx is, or
+// is derived from. A task usually adds its total once and its done units as
+// it works. Nothing is reported when ctx has no Group.
+//
+func Report_progress(ctx context.Context, done, total int64) {
+	o, ok := From_context(ctx)
+	if !ok {
+		return
+	}
+	o.wait_lock.Lock()
+	o.done_units += done
+	o.total_units += total
+	o.wait_lock.Unlock()
+}
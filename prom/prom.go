@@ -0,0 +1,79 @@
+// Copyright 2016 aletheia7. All rights reserved. Use of this source code is
+// governed by a BSD-2-Clause license that can be found in the LICENSE file.
+
+// Package prom provides a prometheus.Collector for a gogroup.Group.
+//
+//	prometheus.MustRegister(prom.New(gg, "main"))
+//
+package prom
+
+import (
+	"github.com/aletheia7/gogroup"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Collector implements prometheus.Collector over gogroup.Group.Stats().
+//
+type Collector struct {
+	g           *gogroup.Group
+	active      *prometheus.Desc
+	tasks       *prometheus.Desc
+	restarts    *prometheus.Desc
+	hedge_calls *prometheus.Desc
+	hedges      *prometheus.Desc
+	errors      *prometheus.Desc
+	canceled    *prometheus.Desc
+	interrupted *prometheus.Desc
+	shutdown    *prometheus.Desc
+}
+
+// New returns a Collector for g. name is set as the "group" label so several
+// Groups can be registered.
+//
+func New(g *gogroup.Group, name string) *Collector {
+	l := prometheus.Labels{"group": name}
+	return &Collector{
+		g:           g,
+		active:      prometheus.NewDesc("gogroup_active_tasks", "Registered tasks that have not ended.", nil, l),
+		tasks:       prometheus.NewDesc("gogroup_tasks_total", "Tasks registered.", nil, l),
+		restarts:    prometheus.NewDesc("gogroup_restarts_total", "Supervise() restarts.", nil, l),
+		hedge_calls: prometheus.NewDesc("gogroup_hedge_calls_total", "Hedge() calls.", nil, l),
+		hedges:      prometheus.NewDesc("gogroup_hedges_total", "Hedge() second calls.", nil, l),
+		errors:      prometheus.NewDesc("gogroup_errors_total", "Non-nil errors passed to Set_err().", nil, l),
+		canceled:    prometheus.NewDesc("gogroup_canceled", "1 if the group is canceled.", nil, l),
+		interrupted: prometheus.NewDesc("gogroup_interrupted", "1 if the group received a signal.", nil, l),
+		shutdown:    prometheus.NewDesc("gogroup_shutdown_seconds", "Cancelation until all tasks ended.", nil, l),
+	}
+}
+
+func (o *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- o.active
+	ch <- o.tasks
+	ch <- o.restarts
+	ch <- o.hedge_calls
+	ch <- o.hedges
+	ch <- o.errors
+	ch <- o.canceled
+	ch <- o.interrupted
+	ch <- o.shutdown
+}
+
+func (o *Collector) Collect(ch chan<- prometheus.Metric) {
+	s := o.g.Stats()
+	ch <- prometheus.MustNewConstMetric(o.active, prometheus.GaugeValue, float64(s.Active))
+	ch <- prometheus.MustNewConstMetric(o.tasks, prometheus.CounterValue, float64(s.Registered))
+	ch <- prometheus.MustNewConstMetric(o.restarts, prometheus.CounterValue, float64(s.Restarts))
+	ch <- prometheus.MustNewConstMetric(o.hedge_calls, prometheus.CounterValue, float64(s.Hedge_calls))
+	ch <- prometheus.MustNewConstMetric(o.hedges, prometheus.CounterValue, float64(s.Hedges))
+	ch <- prometheus.MustNewConstMetric(o.errors, prometheus.CounterValue, float64(s.Errors))
+	ch <- prometheus.MustNewConstMetric(o.canceled, prometheus.GaugeValue, bool_float(s.Canceled))
+	ch <- prometheus.MustNewConstMetric(o.interrupted, prometheus.GaugeValue, bool_float(s.Interrupted))
+	ch <- prometheus.MustNewConstMetric(o.shutdown, prometheus.GaugeValue, s.Shutdown.Seconds())
+}
+
+func bool_float(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,282 @@
+package gogroup
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var Err_queue_full = errors.New("gogroup: task queue full")
+
+// Overflow is what Submit() does when With_limit() tasks are running and the
+// With_queue() queue is full.
+//
+type Overflow int
+
+const (
+	Overflow_block   Overflow = iota // block until queued or the Group is canceled
+	Overflow_timeout                 // block up to the With_queue() timeout
+	Overflow_error                   // return Err_queue_full
+	Overflow_shed                    // drop the lowest priority queued task, or return Err_queue_full
+)
+
+type admission struct {
+	limit    int // < 0 is unlimited
+	size     int
+	overflow Overflow
+	timeout  time.Duration
+	slots    chan struct{} // running + queued tasks
+	lock     sync.Mutex
+	running  int
+	pending  []queued
+	aimd     *aimd // With_adaptive_limit()
+}
+
+type queued struct {
+	index    int
+	name     string
+	f        func() error
+	priority int
+}
+
+func (o *Group) admission() *admission {
+	if o.admit == nil {
+		o.admit = &admission{limit: -1}
+	}
+	return o.admit
+}
+
+// With_limit() allows at most n tasks started with Go(), Go_name(), or
+// Submit() to run at once. Without With_queue(), Go() blocks until a running
+// task ends. n < 1 is no limit.
+//
+func With_limit(n int) option {
+	return func(o *Group) {
+		if n < 1 {
+			n = -1
+		}
+		o.admission().limit = n
+	}
+}
+
+// With_queue() queues up to size tasks while With_limit() tasks are
+// running. A queued task is registered but has no goroutine until it runs.
+// overflow sets what Submit() does when the queue is full; timeout is used
+// with Overflow_timeout. Queued tasks are dropped when the Group is canceled.
+//
+func With_queue(size int, overflow Overflow, timeout time.Duration) option {
+	return func(o *Group) {
+		a := o.admission()
+		a.size, a.overflow, a.timeout = size, overflow, timeout
+	}
+}
+
+// Submit is Go_name() returning Err_queue_full, Err_shed, Err_draining,
+// Err_done, or the Group context error, when the task cannot be started or
+// queued. Go() and Go_name() drop the task in that case, and pass
+// Err_queue_full and Err_shed to Set_err().
+//
+func (o *Group) Submit(name string, f func() error) error {
+	return o.Submit_priority(name, 0, f)
+}
+
+// Submit_priority is Submit() with a priority. With With_limit(), a queued
+// task with a higher priority runs first; tasks of the same priority run in
+// submission order. With Overflow_shed, a full queue drops its lowest
+// priority task, the most recent one, to queue a task of a higher priority.
+// The dropped task is unregistered without running and Err_queue_full is
+// passed to Set_err() for it, as its Submit() already returned nil.
+//
+func (o *Group) Submit_priority(name string, priority int, f func() error) error {
+	if o.shed(name) {
+		return Err_shed
+	}
+	a := o.admit
+	if a == nil || o.sequential || !a.limited() {
+		index, err := o.try_register(name, !o.errgroup)
+		if err != nil {
+			return err
+		}
+		o.start(index, name, f)
+		return nil
+	}
+	o.wait_lock.Lock()
+	err := o.closed()
+	o.wait_lock.Unlock()
+	if err != nil {
+		return err
+	}
+	if err := a.acquire(o, priority); err != nil {
+		return err
+	}
+	a.admit(o, name, priority, f)
+	return nil
+}
+
+// admit starts or queues f after acquire().
+//
+func (o *admission) admit(g *Group, name string, priority int, f func() error) {
+	index := g.register(name, !g.errgroup)
+	if index == 0 {
+		<-o.slots
+		return
+	}
+	o.lock.Lock()
+	if o.running < o.limit {
+		o.running++
+		o.lock.Unlock()
+		g.start(index, name, o.wrap(g, f))
+		return
+	}
+	i := len(o.pending)
+	for 0 < i && o.pending[i-1].priority < priority {
+		i--
+	}
+	o.pending = append(o.pending, queued{})
+	copy(o.pending[i+1:], o.pending[i:])
+	o.pending[i] = queued{index: index, name: name, f: f, priority: priority}
+	o.lock.Unlock()
+}
+
+// try_admit starts f after its slot is acquired only when the limit allows
+// it to run now, and reports whether it did.
+//
+func (o *admission) try_admit(g *Group, f func() error) bool {
+	o.lock.Lock()
+	if o.limit <= o.running {
+		o.lock.Unlock()
+		<-o.slots
+		return false
+	}
+	o.running++
+	o.lock.Unlock()
+	index := g.register("", !g.errgroup)
+	if index == 0 {
+		o.next(g)
+		return false
+	}
+	g.start(index, "", o.wrap(g, f))
+	return true
+}
+
+func (o *admission) acquire_slots() {
+	if o.slots == nil {
+		o.lock.Lock()
+		if o.slots == nil {
+			n := o.limit
+			if o.aimd != nil {
+				n = o.aimd.max
+			}
+			o.slots = make(chan struct{}, n+o.size)
+		}
+		o.lock.Unlock()
+	}
+}
+
+func (o *admission) acquire(g *Group, priority int) error {
+	o.acquire_slots()
+	select {
+	case o.slots <- struct{}{}:
+		return nil
+	default:
+	}
+	var timeout <-chan time.Time
+	switch o.overflow {
+	case Overflow_error:
+		return Err_queue_full
+	case Overflow_shed:
+		if o.shed(g, priority) {
+			return nil
+		}
+		return Err_queue_full
+	case Overflow_timeout:
+		t := time.NewTimer(o.timeout)
+		defer t.Stop()
+		timeout = t.C
+	}
+	select {
+	case o.slots <- struct{}{}:
+		return nil
+	case <-timeout:
+		return Err_queue_full
+	case <-g.Done():
+		return g.Err()
+	}
+}
+
+func (o *admission) wrap(g *Group, f func() error) func() error {
+	if o.aimd == nil {
+		return func() error {
+			defer o.next(g)
+			return f()
+		}
+	}
+	return func() (err error) {
+		start, ok := time.Now(), false
+		defer func() {
+			o.lock.Lock()
+			o.feedback(time.Since(start), ok, err)
+			o.lock.Unlock()
+			o.next(g)
+		}()
+		err = f()
+		ok = true
+		return
+	}
+}
+
+// shed drops the most recent queued task of the lowest priority when it is
+// lower than priority. Its slot is kept for the caller.
+//
+func (o *admission) shed(g *Group, priority int) bool {
+	o.lock.Lock()
+	n := len(o.pending)
+	if n == 0 || priority <= o.pending[n-1].priority {
+		o.lock.Unlock()
+		return false
+	}
+	q := o.pending[n-1]
+	o.pending[n-1] = queued{}
+	o.pending = o.pending[:n-1]
+	o.lock.Unlock()
+	if g.log != nil {
+		g.log.Warn("task shed", "index", q.index, "name", q.name, "priority", q.priority)
+	}
+	g.wait_lock.Lock()
+	if t, ok := g.wait_register.get(q.index); ok {
+		t.keep = true // a dropped task does not cancel the Group
+	}
+	g.wait_lock.Unlock()
+	g.set_err(Err_queue_full, &Task_error{Name: q.name, Err: Err_queue_full})
+	g.unregister(q.index, nil)
+	return true
+}
+
+// next frees the slot of an ended task and starts the next queued task.
+//
+func (o *admission) next(g *Group) {
+	<-o.slots
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	o.running--
+	for o.running < o.limit && 0 < len(o.pending) {
+		q := o.pending[0]
+		o.pending[0] = queued{}
+		o.pending = o.pending[1:]
+		if g.Err() == nil {
+			o.running++
+			g.start(q.index, q.name, o.wrap(g, q.f))
+			continue
+		}
+		<-o.slots
+		g.unregister(q.index, nil)
+	}
+}
+
+// limited reports if a limit is set.
+//
+func (o *admission) limited() bool {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	return 0 <= o.limit
+}
@@ -0,0 +1,54 @@
+package gogroup
+
+import (
+	"context"
+	"errors"
+	"runtime/debug"
+)
+
+// Err_race_empty is returned by Race() without funcs.
+//
+var Err_race_empty = errors.New("gogroup: race without funcs")
+
+// Race runs each of fs as a task of g and returns the first successful
+// result. The context of the other tasks is then canceled. When all fail the
+// errors are joined and returned, and Err_race_empty is returned without fs.
+// The task errors are not passed to Set_err() and the tasks do not cancel g.
+// A panic in a func is its error as a *Panic_error, then goes on to
+// With_recover().
+//
+func Race[T any](g *Group, fs ...func(ctx context.Context) (T, error)) (T, error) {
+	if len(fs) == 0 {
+		var zero T
+		return zero, Err_race_empty
+	}
+	ctx, cancel := context.WithCancel(g)
+	defer cancel()
+	results := make(chan Result[T], len(fs))
+	for _, f := range fs {
+		f := f
+		if err := g.spawn("race", false, func() error {
+			defer func() {
+				if p := recover(); p != nil {
+					results <- Result[T]{Err: &Panic_error{Value: p, Stack: debug.Stack()}}
+					panic(p)
+				}
+			}()
+			v, err := f(ctx)
+			results <- Result[T]{Val: v, Err: err}
+			return nil
+		}); err != nil {
+			results <- Result[T]{Err: err}
+		}
+	}
+	errs := make([]error, 0, len(fs))
+	for range fs {
+		r := <-results
+		if r.Err == nil {
+			return r.Val, nil
+		}
+		errs = append(errs, r.Err)
+	}
+	var zero T
+	return zero, errors.Join(errs...)
+}
@@ -0,0 +1,57 @@
+package gogroup
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func recovering() *Group {
+	return New(With_cancel_nowait(context.Background()), With_recover(func(task string, recovered interface{}, stack []byte) error {
+		return nil
+	}))
+}
+
+func TestRace_panic(t *testing.T) {
+	g := recovering()
+	done := make(chan error, 1)
+	go func() {
+		_, err := Race(g, func(ctx context.Context) (int, error) { panic("race") })
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		var p *Panic_error
+		if !errors.As(err, &p) {
+			t.Fatalf("Race() = %v, want a *Panic_error", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Race() did not return after a panic")
+	}
+	g.Cancel()
+	g.Wait()
+}
+
+func TestHedge_panic(t *testing.T) {
+	g := recovering()
+	done := make(chan error, 1)
+	go func() {
+		_, err := Hedge(g, time.Millisecond, func(ctx context.Context) (int, error) {
+			time.Sleep(10 * time.Millisecond)
+			panic("hedge")
+		})
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		var p *Panic_error
+		if !errors.As(err, &p) {
+			t.Fatalf("Hedge() = %v, want a *Panic_error", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Hedge() did not return after a panic")
+	}
+	g.Cancel()
+	g.Wait()
+}
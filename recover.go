@@ -0,0 +1,30 @@
+package gogroup
+
+import "runtime/debug"
+
+// With_recover() recovers a panic in a Go() task and calls f with the task
+// name, the recovered value and the stack of the panic. The error f returns
+// becomes the task error; a nil error ends the task as if it returned nil.
+// f may panic again, or report to a crash service.
+//
+func With_recover(f func(task string, recovered interface{}, stack []byte) error) option {
+	return func(o *Group) {
+		o.recover_hook = f
+	}
+}
+
+// run_recover is run() with With_recover(). stack is the stack of a
+// recovered panic.
+//
+func (o *Group) run_recover(name string, f func() error) (stack []byte, err error) {
+	if o.recover_hook == nil {
+		return nil, o.run(name, f)
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			stack = debug.Stack()
+			err = o.recover_hook(name, r, stack)
+		}
+	}()
+	return nil, o.run(name, f)
+}
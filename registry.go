@@ -0,0 +1,107 @@
+package gogroup
+
+import "math/bits"
+
+// slot_bits of a task index are its slot in the registry; the higher bits
+// are the generation of the slot.
+//
+const (
+	slot_bits = 20
+	slot_max  = 1 << slot_bits
+	gen_max   = 1<<(bits.UintSize-1-slot_bits) - 1
+)
+
+// registry holds the running tasks of a Group in a slice of slots. The slot
+// and task of an ended task are reused by the next task, so Register() and
+// Unregister() neither hash nor allocate once the slice has grown. An index
+// carries the generation of its slot, so a stale index, e.g. from a second
+// Unregister(), does not find the task now in the slot. wait_lock must be
+// held.
+//
+type registry struct {
+	slots []reg_slot
+	free  []int
+	n     int
+}
+
+type reg_slot struct {
+	gen  int
+	used bool
+	t    *task // kept when the slot is free, for reuse
+}
+
+// add copies t into a free slot and returns its index and the stored task.
+//
+func (o *registry) add(t *task) (int, *task) {
+	var i int
+	if n := len(o.free); 0 < n {
+		i, o.free = o.free[n-1], o.free[:n-1]
+	} else {
+		if slot_max <= len(o.slots) {
+			panic("gogroup: too many running tasks")
+		}
+		i = len(o.slots)
+		o.slots = append(o.slots, reg_slot{})
+	}
+	s := &o.slots[i]
+	if s.gen++; gen_max < s.gen {
+		s.gen = 1
+	}
+	if s.t == nil {
+		s.t = new(task)
+	}
+	*s.t = *t
+	s.used = true
+	o.n++
+	return s.gen<<slot_bits | i, s.t
+}
+
+func (o *registry) get(index int) (*task, bool) {
+	i := index & (slot_max - 1)
+	if index <= 0 || len(o.slots) <= i {
+		return nil, false
+	}
+	s := &o.slots[i]
+	if !s.used || s.gen != index>>slot_bits {
+		return nil, false
+	}
+	return s.t, true
+}
+
+// remove frees the slot of index. The returned task is valid until the
+// slot is reused.
+//
+func (o *registry) remove(index int) (*task, bool) {
+	t, ok := o.get(index)
+	if ok {
+		i := index & (slot_max - 1)
+		o.slots[i].used = false
+		o.free = append(o.free, i)
+		o.n--
+	}
+	return t, ok
+}
+
+func (o *registry) len() int {
+	return o.n
+}
+
+// each calls f with each running task.
+//
+func (o *registry) each(f func(index int, t *task)) {
+	for i := range o.slots {
+		if s := &o.slots[i]; s.used {
+			f(s.gen<<slot_bits|i, s.t)
+		}
+	}
+}
+
+// task_before orders Task_info by Start, then Index, as indexes of reused
+// slots are not in start order.
+//
+func task_before(a, b Task_info) bool {
+	if a.Start.Equal(b.Start) {
+		return a.Index < b.Index
+	}
+	return a.Start.Before(b.Start)
+}
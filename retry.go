@@ -0,0 +1,104 @@
+package gogroup
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+)
+
+// Backoff decides if and when Go_retry() calls f again.
+//
+type Backoff interface {
+	// Next returns the delay before retry n (starting at 1) after err, or
+	// false to stop retrying.
+	Next(n int, err error) (time.Duration, bool)
+}
+
+type backoff_func func(n int, err error) (time.Duration, bool)
+
+func (o backoff_func) Next(n int, err error) (time.Duration, bool) {
+	return o(n, err)
+}
+
+// Constant retries every d. max_retries 0 is unlimited.
+//
+func Constant(d time.Duration, max_retries int) Backoff {
+	return backoff_func(func(n int, err error) (time.Duration, bool) {
+		return d, max_retries == 0 || n <= max_retries
+	})
+}
+
+// Exponential retries after min, doubling up to max. max_retries 0 is
+// unlimited.
+//
+func Exponential(min, max time.Duration, max_retries int) Backoff {
+	p := Restart_policy{Min: min, Max: max, Factor: 2}
+	return backoff_func(func(n int, err error) (time.Duration, bool) {
+		return p.backoff(n), max_retries == 0 || n <= max_retries
+	})
+}
+
+// Jittered randomizes each delay of b to 0..delay.
+//
+func Jittered(b Backoff) Backoff {
+	return backoff_func(func(n int, err error) (time.Duration, bool) {
+		d, ok := b.Next(n, err)
+		if 0 < d {
+			d = time.Duration(rand.Int63n(int64(d) + 1))
+		}
+		return d, ok
+	})
+}
+
+// Retry_if retries with b only when retryable(err) is true.
+//
+func Retry_if(b Backoff, retryable func(error) bool) Backoff {
+	return backoff_func(func(n int, err error) (time.Duration, bool) {
+		if !retryable(err) {
+			return 0, false
+		}
+		return b.Next(n, err)
+	})
+}
+
+type permanent struct {
+	err error
+}
+
+func (o *permanent) Error() string { return o.err.Error() }
+func (o *permanent) Unwrap() error { return o.err }
+
+// Permanent marks err as not retryable. Go_retry() ends with err unwrapped.
+//
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanent{err: err}
+}
+
+// Go_retry calls f as a Go() task, retrying errors according to b. The task
+// ends when f returns nil, a Permanent() error, b stops retrying, or the Group
+// is canceled. The last error is passed to Set_err().
+//
+func (o *Group) Go_retry(f func(ctx context.Context) error, b Backoff) {
+	o.Go(func() error {
+		for n := 1; ; n++ {
+			err := f(o)
+			var p *permanent
+			switch {
+			case err == nil:
+				return nil
+			case errors.As(err, &p):
+				return p.err
+			case o.Err() != nil:
+				return err
+			}
+			d, ok := b.Next(n, err)
+			if !ok || !sleep(o, d) {
+				return err
+			}
+		}
+	})
+}
@@ -0,0 +1,59 @@
+package gogroup
+
+import (
+	"context"
+	"errors"
+	"os"
+)
+
+// Run creates a Group with opt, calls f, and returns an exit code after
+// Wait(). An error from f is passed to Set_err() and cancels the Group.
+// The exit code is Exit_code() of the Wait() error, the Group cause, and
+// Err_interrupted when the Group was interrupted by a signal: by default 130
+// for a signal, 124 when canceled by a deadline or With_max_lifetime(), 1
+// when Wait() returns an error, else 0. The Group is made With_empty_wait(nil)
+// before opt, so when f does its work inline and registers no task, Run()
+// returns once f does.
+//
+func Run(f func(g *Group) error, opt ...option) int {
+	g := New(append([]option{With_empty_wait(nil)}, opt...)...)
+	if err := f(g); err != nil {
+		g.Set_err(err)
+		g.Cancel()
+	}
+	return g.exit_code(g.Wait())
+}
+
+// Main is Run() followed by os.Exit() with the exit code, for use as the
+// body of main().
+//
+func Main(f func(g *Group) error, opt ...option) {
+	os.Exit(Run(f, opt...))
+}
+
+// exit_code is Exit_code() of err joined with Err_interrupted when the Group
+// was interrupted, and with the context.Cause() when it has a mapping.
+//
+func (o *Group) exit_code(err error) int {
+	if cause := context.Cause(o); Exit_code(cause) != Exit_error {
+		err = errors.Join(cause, err)
+	}
+	if o.Is_interrupted() {
+		err = errors.Join(Err_interrupted, err)
+	}
+	return Exit_code(err)
+}
+
+// Scope creates a With_errgroup() Group derived from ctx with opt, calls f,
+// and always Waits for the tasks before returning, so no task outlives the
+// call. An error from f cancels the Group. The error of f and the Wait()
+// error are joined with errors.Join().
+//
+func Scope(ctx context.Context, f func(g *Group) error, opt ...option) error {
+	g := New(append([]option{With_cancel_nowait(ctx), With_errgroup()}, opt...)...)
+	err := f(g)
+	if err != nil {
+		g.Cancel()
+	}
+	return errors.Join(err, g.Wait())
+}
@@ -0,0 +1,47 @@
+package gogroup
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestScope_cancel_child(t *testing.T) {
+	e := errors.New("f")
+	done := make(chan error, 1)
+	go func() {
+		done <- Scope(context.Background(), func(g *Group) error {
+			c := New(With_cancel(g))
+			c.Go(func() error {
+				<-c.Done()
+				return nil
+			})
+			g.Go(func() error { return nil })
+			return e
+		})
+	}()
+	select {
+	case err := <-done:
+		if !errors.Is(err, e) {
+			t.Fatalf("Scope() = %v, want %v", err, e)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Scope() did not return with a With_cancel() child")
+	}
+}
+
+func TestRun_inline(t *testing.T) {
+	done := make(chan int, 1)
+	go func() {
+		done <- Run(func(g *Group) error { return nil }, With_cancel_nowait(context.Background()))
+	}()
+	select {
+	case code := <-done:
+		if code != 0 {
+			t.Fatalf("Run() = %v, want 0", code)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run() did not return without tasks")
+	}
+}
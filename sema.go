@@ -0,0 +1,97 @@
+package gogroup
+
+import (
+	"container/list"
+	"sync"
+)
+
+// With_weight() sets the capacity shared by Acquire()/Release(). Without
+// With_weight(), Acquire() does not block.
+//
+func With_weight(size int64) option {
+	return func(o *Group) {
+		o.sema = &sema{size: size}
+	}
+}
+
+type sema struct {
+	lock    sync.Mutex
+	size    int64
+	cur     int64
+	waiters list.List
+}
+
+type sema_waiter struct {
+	n     int64
+	ready chan struct{}
+}
+
+// Acquire blocks until n of the With_weight() capacity is available or the
+// Group is canceled. Waiters are served in order. Returns the Group context
+// error when canceled. Release(n) must be called after a nil return.
+//
+func (o *Group) Acquire(n int64) error {
+	s := o.sema
+	if s == nil {
+		return nil
+	}
+	s.lock.Lock()
+	if n <= s.size-s.cur && s.waiters.Len() == 0 {
+		s.cur += n
+		s.lock.Unlock()
+		return nil
+	}
+	if s.size < n {
+		s.lock.Unlock()
+		<-o.Done()
+		return o.Err()
+	}
+	w := &sema_waiter{n: n, ready: make(chan struct{})}
+	e := s.waiters.PushBack(w)
+	s.lock.Unlock()
+	select {
+	case <-w.ready:
+		return nil
+	case <-o.Done():
+		s.lock.Lock()
+		select {
+		case <-w.ready:
+			// Acquired after the cancel; give it back.
+			s.cur -= n
+		default:
+			s.waiters.Remove(e)
+		}
+		s.notify()
+		s.lock.Unlock()
+		return o.Err()
+	}
+}
+
+// Release returns n to the With_weight() capacity. Will panic if more is
+// released than acquired.
+//
+func (o *Group) Release(n int64) {
+	s := o.sema
+	if s == nil {
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.cur -= n
+	if s.cur < 0 {
+		panic("gogroup: Release() more than Acquire()")
+	}
+	s.notify()
+}
+
+func (o *sema) notify() {
+	for e := o.waiters.Front(); e != nil; e = o.waiters.Front() {
+		w := e.Value.(*sema_waiter)
+		if o.size-o.cur < w.n {
+			return
+		}
+		o.cur += w.n
+		o.waiters.Remove(e)
+		close(w.ready)
+	}
+}
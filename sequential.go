@@ -0,0 +1,32 @@
+package gogroup
+
+// With_sequential() runs the tasks of Go(), Go_name() and Submit() one at a
+// time, in submission order, on the goroutine calling Wait(), so tests of
+// pipeline logic do not depend on goroutine scheduling. A task submitted by
+// a running task runs after the tasks already submitted. Concurrency limits
+// are not applied. Tasks that wait for each other, e.g. over an unbuffered
+// channel, deadlock. Register() tasks, and tasks submitted after the
+// sequence has been run, are not changed.
+//
+func With_sequential() option {
+	return func(o *Group) {
+		o.sequential = true
+	}
+}
+
+// run_sequence runs the tasks queued by start() until there are none.
+//
+func (o *Group) run_sequence() {
+	for {
+		o.wait_lock.Lock()
+		if len(o.sequence) == 0 {
+			o.sequenced = true
+			o.wait_lock.Unlock()
+			return
+		}
+		q := o.sequence[0]
+		o.sequence = o.sequence[1:]
+		o.wait_lock.Unlock()
+		o.exec(q.index, q.name, q.f)
+	}
+}
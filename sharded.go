@@ -0,0 +1,137 @@
+package gogroup
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// Sharded_pool is a Pool where each worker owns a queue. Submit() spreads
+// funcs over the queues; an idle worker takes from the back of its own queue,
+// then steals from the front of the others. The queues are not bounded.
+// Errors, panics, and cancelation are handled as in Pool.
+//
+type Sharded_pool struct {
+	g      *Group
+	shards []*shard
+	next   atomic.Uint64
+	work   chan struct{}
+	lock   sync.RWMutex
+	closed bool
+}
+
+type shard struct {
+	lock  sync.Mutex
+	queue []func(ctx context.Context) error
+	wake  chan struct{}
+}
+
+// New_sharded_pool starts workers worker tasks in g.
+//
+func New_sharded_pool(g *Group, workers int) *Sharded_pool {
+	if workers < 1 {
+		workers = 1
+	}
+	r := &Sharded_pool{g: g, shards: make([]*shard, workers), work: make(chan struct{}, workers)}
+	for i := range r.shards {
+		r.shards[i] = &shard{wake: make(chan struct{}, 1)}
+	}
+	for i := range r.shards {
+		i := i
+		if g.spawn("pool", false, func() error { return r.run(i) }) != nil {
+			r.closed = i == 0
+			break
+		}
+	}
+	return r
+}
+
+// Submit queues f. Returns Err_pool_closed after the Group is canceled.
+//
+func (o *Sharded_pool) Submit(f func(ctx context.Context) error) error {
+	o.lock.RLock()
+	defer o.lock.RUnlock()
+	if o.closed {
+		return Err_pool_closed
+	}
+	s := o.shards[o.next.Add(1)%uint64(len(o.shards))]
+	s.lock.Lock()
+	s.queue = append(s.queue, f)
+	s.lock.Unlock()
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	select {
+	case o.work <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Depths returns the queue length of each worker.
+//
+func (o *Sharded_pool) Depths() []int {
+	r := make([]int, len(o.shards))
+	for i, s := range o.shards {
+		s.lock.Lock()
+		r[i] = len(s.queue)
+		s.lock.Unlock()
+	}
+	return r
+}
+
+func (o *Sharded_pool) run(i int) error {
+	own := o.shards[i]
+	for {
+		if f := o.take(i); f != nil {
+			if err := call(o.g, f); err != nil {
+				o.g.Set_err(err)
+				o.g.Cancel()
+			}
+			continue
+		}
+		select {
+		case <-own.wake:
+		case <-o.work:
+		case <-o.g.Done():
+			o.lock.Lock()
+			o.closed = true
+			o.lock.Unlock()
+			for f := o.take(i); f != nil; f = o.take(i) {
+				if err := call(o.g, f); err != nil {
+					o.g.Set_err(err)
+				}
+			}
+			return nil
+		}
+	}
+}
+
+// take pops from the back of shard i, else steals from the front of another.
+//
+func (o *Sharded_pool) take(i int) func(ctx context.Context) error {
+	s := o.shards[i]
+	s.lock.Lock()
+	if n := len(s.queue); 0 < n {
+		f := s.queue[n-1]
+		s.queue[n-1] = nil
+		s.queue = s.queue[:n-1]
+		s.lock.Unlock()
+		return f
+	}
+	s.lock.Unlock()
+	for j := 1; j < len(o.shards); j++ {
+		v := o.shards[(i+j)%len(o.shards)]
+		v.lock.Lock()
+		if 0 < len(v.queue) {
+			f := v.queue[0]
+			v.queue[0] = nil
+			v.queue = v.queue[1:]
+			v.lock.Unlock()
+			return f
+		}
+		v.lock.Unlock()
+	}
+	return nil
+}
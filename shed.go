@@ -0,0 +1,75 @@
+package gogroup
+
+import (
+	"errors"
+	"time"
+)
+
+// Err_shed is returned by Submit() when the With_load_shedding() func rejects
+// the task.
+//
+var Err_shed = errors.New("gogroup: task shed")
+
+// Load is the Group load passed to the With_load_shedding() func.
+//
+type Load struct {
+	Running int           // registered tasks not queued
+	Queued  int           // With_queue() tasks waiting to run
+	Latency time.Duration // moving average of recent task durations from Submit(), weight 1/8
+}
+
+// With_load_shedding() calls shed on each Submit(), Go() and Go_name(), and
+// rejects the task with Err_shed when it returns true, to protect a server
+// that fans work into a shared Group from overload. Register() is not
+// checked.
+//
+//	With_load_shedding(func(name string, l Load) bool {
+//		return 100 < l.Queued || time.Second < l.Latency
+//	})
+//
+func With_load_shedding(shed func(name string, load Load) bool) option {
+	return func(o *Group) {
+		o.shed_hook = shed
+	}
+}
+
+// Load returns the current Load of the Group. Latency is only measured with
+// With_load_shedding().
+//
+func (o *Group) Load() (r Load) {
+	if a := o.admit; a != nil {
+		a.lock.Lock()
+		r.Queued = len(a.pending)
+		a.lock.Unlock()
+	}
+	o.wait_lock.Lock()
+	r.Running = o.wait_register.len() - r.Queued
+	r.Latency = o.latency
+	o.wait_lock.Unlock()
+	if r.Running < 0 {
+		r.Running = 0
+	}
+	return
+}
+
+// shed reports if the With_load_shedding() func rejects the task name.
+//
+func (o *Group) shed(name string) bool {
+	if o.shed_hook == nil || !o.shed_hook(name, o.Load()) {
+		return false
+	}
+	if o.log != nil {
+		o.log.Warn("task shed", "name", name)
+	}
+	return true
+}
+
+// measure adds d to the Load Latency. wait_lock must be held.
+//
+func (o *Group) measure(d time.Duration) {
+	if o.latency == 0 {
+		o.latency = d
+		return
+	}
+	o.latency += (d - o.latency) / 8
+}
@@ -0,0 +1,89 @@
+package gogroup
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// watcher is the process wide os.Interrupt/SIGTERM watcher shared by all
+// Groups, instead of a goroutine and signal.Notify() for each Group.
+//
+var watcher struct {
+	lock   sync.Mutex
+	groups map[*Group]struct{}
+	ch     chan os.Signal
+}
+
+func watch_signals(g *Group) {
+	watcher.lock.Lock()
+	defer watcher.lock.Unlock()
+	if watcher.groups == nil {
+		watcher.groups = map[*Group]struct{}{}
+	}
+	watcher.groups[g] = struct{}{}
+	if watcher.ch == nil {
+		watcher.ch = make(chan os.Signal, 1)
+		signal.Notify(watcher.ch, os.Interrupt, syscall.SIGTERM)
+		go deliver_signals(watcher.ch)
+	}
+}
+
+func unwatch_signals(g *Group) {
+	watcher.lock.Lock()
+	defer watcher.lock.Unlock()
+	if _, ok := watcher.groups[g]; !ok {
+		return
+	}
+	delete(watcher.groups, g)
+	if len(watcher.groups) == 0 {
+		signal.Stop(watcher.ch)
+		close(watcher.ch)
+		watcher.ch = nil
+	}
+}
+
+// deliver_signals interrupts and cancels every watching Group on a signal.
+//
+func deliver_signals(ch chan os.Signal) {
+	for sig := range ch {
+		watcher.lock.Lock()
+		groups := make([]*Group, 0, len(watcher.groups))
+		for g := range watcher.groups {
+			groups = append(groups, g)
+		}
+		watcher.lock.Unlock()
+		for _, g := range groups {
+			g.signal(sig)
+		}
+	}
+}
+
+func (o *Group) signal(sig os.Signal) {
+	if o.Err() == nil {
+		o.interrupt()
+		o.wait_lock.Lock()
+		o.emit(Event{Kind: Event_signal, Signal: sig})
+		o.wait_lock.Unlock()
+		o.Cancel()
+	}
+}
+
+// Inject_signal runs the signal path of the Group as if sig was received:
+// Interrupted is set and the Group is canceled. Tests can use it instead of
+// signaling the test process. sig is ignored unless it is os.Interrupt,
+// syscall.SIGTERM, or a With_notify_context() signal.
+//
+func (o *Group) Inject_signal(sig os.Signal) {
+	watched := []os.Signal{os.Interrupt, syscall.SIGTERM}
+	if o.notify != nil {
+		watched = o.signals
+	}
+	for _, s := range watched {
+		if s == sig {
+			o.signal(sig)
+			return
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package gogroup
+
+// State is the lifecycle state of a Group.
+//
+type State int
+
+const (
+	State_idle      State = iota // no task registered yet
+	State_running                // tasks registered, not canceled
+	State_canceling              // canceled, Wait() not returned
+	State_done                   // Wait() returned, after its hooks ran
+)
+
+func (o State) String() string {
+	switch o {
+	case State_idle:
+		return "idle"
+	case State_running:
+		return "running"
+	case State_canceling:
+		return "canceling"
+	case State_done:
+		return "done"
+	}
+	return "unknown"
+}
+
+func (o *Group) State() State {
+	canceled := o.Err() != nil
+	o.wait_lock.Lock()
+	defer o.wait_lock.Unlock()
+	switch {
+	case o.waited:
+		return State_done
+	case canceled:
+		return State_canceling
+	case o.wait_index == 0 && !o.fast_started.Load():
+		return State_idle
+	}
+	return State_running
+}
+
+// Is_done reports if Wait() has returned, so all tasks have ended and the
+// Defer() and On_done() funcs have run.
+//
+func (o *Group) Is_done() bool {
+	return o.State() == State_done
+}
+
+// Is_canceled reports if the Group context is canceled.
+//
+func (o *Group) Is_canceled() bool {
+	return o.Err() != nil
+}
+
+// Is_interrupted is Group.Interrupted read without a data race.
+//
+func (o *Group) Is_interrupted() bool {
+	o.wait_lock.Lock()
+	defer o.wait_lock.Unlock()
+	return o.Interrupted
+}
+
+// Dying returns a channel closed when the Group is canceled, like
+// tomb.Dying(). It is Done().
+//
+func (o *Group) Dying() <-chan struct{} {
+	return o.Done()
+}
+
+// Dead returns a channel closed when the Group is canceled and all tasks
+// have ended, like tomb.Dead().
+//
+func (o *Group) Dead() <-chan struct{} {
+	o.wait_lock.Lock()
+	defer o.wait_lock.Unlock()
+	if o.dead == nil {
+		o.dead = make(chan struct{})
+		go func() {
+			<-o.Done()
+			o.wg().Wait()
+			close(o.dead)
+		}()
+	}
+	return o.dead
+}
@@ -0,0 +1,61 @@
+package gogroup
+
+import (
+	"sort"
+	"time"
+)
+
+// Stats is a snapshot of the Group state returned by Group.Stats().
+//
+type Stats struct {
+	Active      int    // Register() calls without a matching Unregister()
+	Registered  int    // total Register() calls
+	Err         string // first error from Set_err(), if any
+	Errors      int    // Set_err() calls with a non-nil error
+	Restarts    int    // Supervise() restarts
+	Hedge_calls int    // Hedge() calls
+	Hedges      int    // Hedge() second calls
+	Canceled    bool
+	Interrupted bool
+	Shutdown    time.Duration            // cancelation until Wait() saw all tasks end
+	Cpu         map[string]time.Duration // With_cpu_time(): CPU time per Go_name() name
+	Tasks       []Task_info              // last Task_history ended tasks, then running tasks
+}
+
+// Stats returns a snapshot of the Group. It is safe to call while the Group is
+// running.
+//
+func (o *Group) Stats() (r Stats) {
+	o.wait_lock.Lock()
+	r.Active = o.wait_register.len() + o.fast_len()
+	r.Registered = o.wait_index
+	r.Interrupted = o.Interrupted
+	r.Errors = o.errors
+	r.Restarts = o.restarts
+	r.Hedge_calls = o.hedge_calls
+	r.Hedges = o.hedges
+	r.Shutdown = o.shutdown
+	if 0 < len(o.cpu) {
+		r.Cpu = make(map[string]time.Duration, len(o.cpu))
+		for k, v := range o.cpu {
+			r.Cpu[k] = v
+		}
+	}
+	h := len(o.history)
+	r.Tasks = make([]Task_info, 0, h+o.wait_register.len())
+	r.Tasks = append(r.Tasks, o.history[o.history_next:]...)
+	r.Tasks = append(r.Tasks, o.history[:o.history_next]...)
+	o.wait_register.each(func(index int, t *task) {
+		r.Tasks = append(r.Tasks, t.info(index))
+	})
+	o.wait_lock.Unlock()
+	running := r.Tasks[h:]
+	sort.Slice(running, func(i, j int) bool {
+		return task_before(running[i], running[j])
+	})
+	if err := o.Get_err(); err != nil {
+		r.Err = err.Error()
+	}
+	r.Canceled = o.Err() != nil
+	return
+}
@@ -0,0 +1,78 @@
+package gogroup
+
+import (
+	"context"
+	"runtime/debug"
+	"time"
+)
+
+// Restart_policy controls Supervise(). Zero values use the defaults.
+//
+type Restart_policy struct {
+	Min          time.Duration // first backoff; default 100ms
+	Max          time.Duration // backoff limit; default 30s
+	Factor       float64       // backoff multiplier; default 2
+	Max_restarts int           // restarts before giving up; 0 is unlimited
+}
+
+func (o Restart_policy) backoff(restarts int) time.Duration {
+	min, max, factor := o.Min, o.Max, o.Factor
+	if min <= 0 {
+		min = 100 * time.Millisecond
+	}
+	if max <= 0 {
+		max = 30 * time.Second
+	}
+	if factor < 1 {
+		factor = 2
+	}
+	d := float64(min)
+	for i := 1; i < restarts && d < float64(max); i++ {
+		d *= factor
+	}
+	if float64(max) < d {
+		return max
+	}
+	return time.Duration(d)
+}
+
+// Supervise runs f as a registered task named name. When f returns an error
+// or panics, f is restarted after an exponential backoff instead of ending
+// the task. When f returns nil, or the Group is canceled, the task ends as a
+// Go() task would. After policy.Max_restarts restarts the last error is passed
+// to Set_err() and the task ends. Restarts are counted in Stats().Restarts.
+//
+func (o *Group) Supervise(name string, f func(ctx context.Context) error, policy Restart_policy) {
+	o.Go_name(name, func() error {
+		for restarts := 0; ; {
+			err := call(o, f)
+			if err == nil || o.Err() != nil {
+				return err
+			}
+			if 0 < policy.Max_restarts && policy.Max_restarts <= restarts {
+				return err
+			}
+			restarts++
+			o.wait_lock.Lock()
+			o.restarts++
+			o.wait_lock.Unlock()
+			if o.log != nil {
+				o.log.Warn("task restart", "name", name, "restarts", restarts, "err", err)
+			}
+			if !sleep(o, policy.backoff(restarts)) {
+				return err
+			}
+		}
+	})
+}
+
+// call returns f(ctx) and converts a panic to a *Panic_error.
+//
+func call(ctx context.Context, f func(ctx context.Context) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &Panic_error{Value: r, Stack: debug.Stack()}
+		}
+	}()
+	return f(ctx)
+}
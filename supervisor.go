@@ -0,0 +1,201 @@
+package gogroup
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Service is run by a Supervisor. Serve must return when ctx is done.
+//
+type Service interface {
+	Serve(ctx context.Context) error
+}
+
+// Service_func adapts a func to a Service.
+//
+type Service_func func(ctx context.Context) error
+
+func (o Service_func) Serve(ctx context.Context) error {
+	return o(ctx)
+}
+
+// Strategy decides which children a Supervisor restarts when one fails.
+//
+type Strategy int
+
+const (
+	One_for_one Strategy = iota // restart the failed child
+	One_for_all                 // cancel and restart all children
+)
+
+// Supervisor runs child Services, restarting them according to its Strategy
+// and Restart_policy. A Supervisor is a Service so supervisors form a tree.
+// When Restart_policy.Max_restarts is exceeded the Supervisor cancels its
+// children and Serve returns the last error, escalating the failure to the
+// parent Supervisor. A child returning nil is not restarted.
+//
+type Supervisor struct {
+	name     string
+	strategy Strategy
+	policy   Restart_policy
+	lock     sync.Mutex
+	children []supervised
+	restarts int
+	failures int // restarts since Serve was called
+}
+
+type supervised struct {
+	name string
+	s    Service
+}
+
+func New_supervisor(name string, strategy Strategy, policy Restart_policy) *Supervisor {
+	return &Supervisor{name: name, strategy: strategy, policy: policy}
+}
+
+// Add adds a child Service or *Supervisor. Add must be called before Serve.
+//
+func (o *Supervisor) Add(name string, s Service) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	o.children = append(o.children, supervised{name: name, s: s})
+}
+
+// Restarts returns the number of child restarts by this Supervisor.
+//
+func (o *Supervisor) Restarts() int {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	return o.restarts
+}
+
+// restart counts a restart and reports if the Restart_policy allows it.
+//
+func (o *Supervisor) restart() (time.Duration, bool) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	if 0 < o.policy.Max_restarts && o.policy.Max_restarts <= o.failures {
+		return 0, false
+	}
+	o.restarts++
+	o.failures++
+	return o.policy.backoff(o.failures), true
+}
+
+// Serve runs the children until they all return nil, ctx is done, or the
+// Restart_policy gives up.
+//
+func (o *Supervisor) Serve(ctx context.Context) error {
+	o.lock.Lock()
+	children := append([]supervised(nil), o.children...)
+	o.failures = 0
+	o.lock.Unlock()
+	if o.strategy == One_for_all {
+		return o.serve_all(ctx, children)
+	}
+	return o.serve_one(ctx, children)
+}
+
+func (o *Supervisor) serve_one(ctx context.Context, children []supervised) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		wg       sync.WaitGroup
+		err_once sync.Once
+		err      error
+	)
+	for _, c := range children {
+		wg.Add(1)
+		go func(c supervised) {
+			defer wg.Done()
+			for {
+				e := call(ctx, c.s.Serve)
+				if e == nil || ctx.Err() != nil {
+					return
+				}
+				d, ok := o.restart()
+				if !ok {
+					err_once.Do(func() { err = e })
+					cancel()
+					return
+				}
+				if !sleep(ctx, d) {
+					return
+				}
+			}
+		}(c)
+	}
+	wg.Wait()
+	return err
+}
+
+func (o *Supervisor) serve_all(ctx context.Context, children []supervised) error {
+	if len(children) == 0 {
+		return nil
+	}
+	for {
+		g := New(With_cancel_nowait(ctx))
+		// A child returning nil leaves its siblings running; an error
+		// cancels them. The last child to end cancels g.
+		var lock sync.Mutex
+		left := len(children)
+		for _, c := range children {
+			s := c.s
+			g.spawn(c.name, false, func() error {
+				defer func() {
+					lock.Lock()
+					left--
+					if left == 0 {
+						g.Cancel()
+					}
+					lock.Unlock()
+				}()
+				return call(g, s.Serve)
+			})
+		}
+		err := g.Wait()
+		if err == nil || ctx.Err() != nil {
+			return nil
+		}
+		d, ok := o.restart()
+		if !ok {
+			return err
+		}
+		if !sleep(ctx, d) {
+			return nil
+		}
+	}
+}
+
+// Go_supervisor runs s as a task named after s. An error escalated from the
+// root Supervisor is passed to Set_err().
+//
+func (o *Group) Go_supervisor(s *Supervisor) {
+	o.Go_name(s.name, func() error { return s.Serve(o) })
+}
+
+// sleep waits d and returns false if ctx is done first. The Clock of the
+// Group of ctx is used.
+//
+func sleep(ctx context.Context, d time.Duration) bool {
+	if g, ok := From_context(ctx); ok && g.clock != nil {
+		c := make(chan struct{})
+		t := g.clock.AfterFunc(d, func() { close(c) })
+		defer t.Stop()
+		select {
+		case <-ctx.Done():
+			return false
+		case <-c:
+			return true
+		}
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
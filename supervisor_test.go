@@ -0,0 +1,27 @@
+package gogroup
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSupervisor_all_cancel(t *testing.T) {
+	s := New_supervisor("s", One_for_all, Restart_policy{})
+	started := make(chan struct{}, 2)
+	for _, name := range []string{"a", "b"} {
+		s.Add(name, Service_func(func(ctx context.Context) error {
+			started <- struct{}{}
+			<-ctx.Done()
+			return ctx.Err()
+		}))
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-started
+		<-started
+		cancel()
+	}()
+	if err := s.Serve(ctx); err != nil {
+		t.Fatalf("Serve() = %v after cancel, want nil", err)
+	}
+}
@@ -0,0 +1,14 @@
+package gogroup
+
+// With_synctest() lets the Group run in a testing/synctest bubble: it does
+// not watch os.Interrupt/SIGTERM or the With_drain_signal() signal, so it
+// starts no goroutine and creates no channel that the bubble cannot account
+// for, and With_notify_context() does not call signal.NotifyContext().
+// Inject_signal() still runs the signal path. A Group made in a bubble must
+// use it.
+//
+func With_synctest() option {
+	return func(o *Group) {
+		o.no_signal = true
+	}
+}
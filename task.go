@@ -0,0 +1,266 @@
+package gogroup
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Task_history is the number of ended tasks kept for Stats().Tasks.
+//
+var Task_history = 100
+
+// Task_caller enables capturing the caller of Register()/Go() for
+// Task_info.Caller(). It is most of the cost of Register(); set it to false
+// for groups doing millions of Register()/Unregister() calls.
+//
+var Task_caller = true
+
+// Task_goroutine records the goroutine running each task, for the
+// stacks of Running(), without With_shutdown_watchdog().
+// gogrouptest.Enable_stacks() sets it.
+//
+var Task_goroutine = false
+
+// Task_info describes a task started with Register() or Go().
+//
+type Task_info struct {
+	Index int // returned by Register()
+	Name  string
+	Start time.Time
+	Stop  time.Time // zero while running
+	pcs   [8]uintptr
+}
+
+// Caller returns the file:line that started the task, outside this package,
+// or "" when Task_caller is false.
+//
+func (o Task_info) Caller() string {
+	frames := runtime.CallersFrames(o.pcs[:])
+	for {
+		f, more := frames.Next()
+		if f.PC != 0 && !strings.HasPrefix(f.Function, pkg_path) {
+			return fmt.Sprintf("%v:%v", f.File, f.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+// Duration returns Stop - Start, or the time since Start while running.
+//
+func (o Task_info) Duration() time.Duration {
+	if o.Stop.IsZero() {
+		return time.Since(o.Start)
+	}
+	return o.Stop.Sub(o.Start)
+}
+
+// pkg_path prefixes the functions of this package in stacks.
+//
+const pkg_path = "github.com/aletheia7/gogroup."
+
+type task struct {
+	name  string
+	start time.Time
+	pcs   [8]uintptr // callers of register()
+	slow  *time.Timer
+	keep  bool   // do not cancel the Group when the task ends without error
+	goid  uint64 // goroutine running the task; With_shutdown_watchdog()
+	stack []byte // With_leak_report()
+}
+
+func (o *task) info(index int) Task_info {
+	return Task_info{Index: index, Name: o.name, Start: o.start, pcs: o.pcs}
+}
+
+// add_history keeps the last Task_history ended tasks in a ring, so
+// Unregister() does not copy the history. wait_lock must be held.
+//
+func (o *Group) add_history(t *task, index int) {
+	if Task_history <= 0 {
+		return
+	}
+	ti := t.info(index)
+	ti.Stop = time.Now()
+	switch {
+	case len(o.history) < Task_history && o.history_next == 0:
+		o.history = append(o.history, ti)
+	case len(o.history) == Task_history:
+		o.history[o.history_next] = ti
+		o.history_next = (o.history_next + 1) % Task_history
+	default:
+		// Task_history changed
+		h := make([]Task_info, 0, len(o.history)+1)
+		h = append(h, o.history[o.history_next:]...)
+		h = append(h, o.history[:o.history_next]...)
+		h = append(h, ti)
+		if n := len(h) - Task_history; 0 < n {
+			h = h[n:]
+		}
+		o.history, o.history_next = h, 0
+	}
+}
+
+// With_slow_task() calls f once for each task still running d after it
+// started. f is called from its own goroutine.
+//
+func With_slow_task(d time.Duration, f func(Task_info)) option {
+	return func(o *Group) {
+		o.slow, o.slow_hook = d, f
+	}
+}
+
+// With_cpu_time() records the CPU time used by named tasks started with
+// Go_name(). Each named task is run on a locked OS thread so the thread CPU
+// time can be sampled before and after the task. The totals per name are
+// reported in Stats().Cpu. Only supported on Linux; elsewhere Stats().Cpu is
+// empty.
+//
+func With_cpu_time() option {
+	return func(o *Group) {
+		o.cpu_time = true
+	}
+}
+
+// Go calls f in a new goroutine. f is registered with Register()/Unregister().
+// A non-nil error returned from f is passed to Set_err(); Errors() has it as
+// a *Task_error.
+// The Group is canceled when f returns.
+//
+func (o *Group) Go(f func() error) {
+	if o.fast_go && o.go_fast(f) {
+		return
+	}
+	o.Go_name("", f)
+}
+
+// Go_name is Go() with a task name. A task dropped with Err_queue_full or
+// Err_shed, see Submit(), is passed to Set_err(), so Wait() reports it.
+//
+func (o *Group) Go_name(name string, f func() error) {
+	err := o.Submit(name, f)
+	if err == nil {
+		return
+	}
+	if err == Err_queue_full || err == Err_shed {
+		o.set_err(err, &Task_error{Name: name, Err: err})
+	}
+	if o.log != nil {
+		o.log.Warn("task dropped", "name", name, "err", err)
+	}
+}
+
+// Go_os_thread is Go_name() with f run on its own OS thread, locked with
+// runtime.LockOSThread() until f returns, for cgo libraries or thread local
+// state.
+//
+func (o *Group) Go_os_thread(name string, f func() error) {
+	o.Go_name(name, func() error {
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+		return f()
+	})
+}
+
+// spawn runs f as a registered task. When cancel is false the Group is only
+// canceled when f returns an error. The error of closed() is returned when f
+// is not run; the caller must then release what f would have.
+//
+func (o *Group) spawn(name string, cancel bool, f func() error) error {
+	index, err := o.try_register(name, cancel)
+	if err != nil {
+		return err
+	}
+	o.start(index, name, f)
+	return nil
+}
+
+func (o *Group) start(index int, name string, f func() error) {
+	// sequential is only set by options, so other Groups skip the lock.
+	if o.sequential {
+		o.wait_lock.Lock()
+		if !o.sequenced {
+			o.sequence = append(o.sequence, queued{index: index, name: name, f: f})
+			o.wait_lock.Unlock()
+			return
+		}
+		o.wait_lock.Unlock()
+	}
+	go o.exec(index, name, f)
+}
+
+// exec runs the registered task f.
+//
+func (o *Group) exec(index int, name string, f func() error) {
+	var err error
+	defer func() { o.unregister(index, err) }()
+	if o.goids || Task_goroutine {
+		o.set_goid(index)
+	}
+	var stack []byte
+	if stack, err = o.run_recover(name, f); err != nil {
+		o.set_err(err, o.task_error(index, name, err, stack))
+	}
+}
+
+func (o *Group) set_goid(index int) {
+	id := goid()
+	o.wait_lock.Lock()
+	if t, ok := o.wait_register.get(index); ok {
+		t.goid = id
+	}
+	o.wait_lock.Unlock()
+}
+
+func (o *Group) run(name string, f func() error) error {
+	if o.limiter != nil {
+		if err := o.limiter.Wait(o); err != nil {
+			if o.Err() != nil {
+				return nil
+			}
+			return err
+		}
+	}
+	if !o.cpu_time || name == "" {
+		return f()
+	}
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	start, ok := thread_cpu()
+	defer func() {
+		if end, _ := thread_cpu(); ok {
+			o.wait_lock.Lock()
+			if o.cpu == nil {
+				o.cpu = map[string]time.Duration{}
+			}
+			o.cpu[name] += end - start
+			o.wait_lock.Unlock()
+		}
+	}()
+	return f()
+}
+
+// Len returns the number of running tasks.
+//
+func (o *Group) Len() int {
+	o.wait_lock.Lock()
+	defer o.wait_lock.Unlock()
+	return o.wait_register.len() + o.fast_len()
+}
+
+// Tasks returns the running tasks ordered by Start.
+//
+func (o *Group) Tasks() []Task_info {
+	o.wait_lock.Lock()
+	r := make([]Task_info, 0, o.wait_register.len())
+	o.wait_register.each(func(index int, t *task) {
+		r = append(r, t.info(index))
+	})
+	o.wait_lock.Unlock()
+	sort.Slice(r, func(i, j int) bool { return task_before(r[i], r[j]) })
+	return r
+}
@@ -0,0 +1,84 @@
+package gogroup
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Task_error is the error of a Go() task, with the task name and timing.
+// Wait() returns the error of the task itself, as errgroup does; Errors()
+// has a Task_error for each task error. Use errors.Is()/errors.Unwrap() for
+// the error returned by the task.
+//
+type Task_error struct {
+	Name     string
+	Start    time.Time
+	Duration time.Duration
+	Stack    []byte // stack of a recovered panic, else nil
+	Err      error
+}
+
+func (o *Task_error) Error() string {
+	if o.Name == "" {
+		return o.Err.Error()
+	}
+	return fmt.Sprintf("%v: %v", o.Name, o.Err)
+}
+
+func (o *Task_error) Unwrap() error {
+	return o.Err
+}
+
+// Format prints the panic stack after the error with %+v.
+//
+func (o *Task_error) Format(f fmt.State, verb rune) {
+	format_error(f, verb, o.Error(), o.Stack)
+}
+
+// Panic_error is a recovered panic. Stack is captured where the panic was
+// recovered, so it includes the panicking frames.
+//
+type Panic_error struct {
+	Value interface{}
+	Stack []byte
+}
+
+func (o *Panic_error) Error() string {
+	return fmt.Sprintf("panic: %v", o.Value)
+}
+
+// Format prints the panic stack after the error with %+v.
+//
+func (o *Panic_error) Format(f fmt.State, verb rune) {
+	format_error(f, verb, o.Error(), o.Stack)
+}
+
+func format_error(f fmt.State, verb rune, msg string, stack []byte) {
+	switch {
+	case verb == 'v' && f.Flag('+') && 0 < len(stack):
+		fmt.Fprintf(f, "%v\n%s", msg, stack)
+	case verb == 'q':
+		fmt.Fprintf(f, "%q", msg)
+	default:
+		fmt.Fprint(f, msg)
+	}
+}
+
+// task_error makes the Task_error of err. stack is the stack of a panic
+// recovered by With_recover(), else the stack of a Panic_error in err.
+//
+func (o *Group) task_error(index int, name string, err error, stack []byte) *Task_error {
+	r := &Task_error{Name: name, Err: err, Stack: stack}
+	var p *Panic_error
+	if stack == nil && errors.As(err, &p) {
+		r.Stack = p.Stack
+	}
+	o.wait_lock.Lock()
+	if t, ok := o.wait_register.get(index); ok {
+		r.Start = t.start
+		r.Duration = time.Since(t.start)
+	}
+	o.wait_lock.Unlock()
+	return r
+}
@@ -0,0 +1,92 @@
+package gogroup
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+type tick_option func(o *ticker)
+
+type ticker struct {
+	jitter         time.Duration
+	jitter_percent float64
+}
+
+// With_jitter() adds a random -d..+d to each Go_tick() interval. d is at
+// most half the interval, so an interval is never shorter than half.
+//
+func With_jitter(d time.Duration) tick_option {
+	return func(o *ticker) {
+		o.jitter = d
+	}
+}
+
+// With_jitter_percent() adds a random -p..+p percent of the interval to each
+// Go_tick() interval. p is at most 50.
+//
+func With_jitter_percent(p float64) tick_option {
+	return func(o *ticker) {
+		o.jitter_percent = p
+	}
+}
+
+func (o *ticker) next(interval time.Duration) time.Duration {
+	j := o.jitter
+	if 0 < o.jitter_percent {
+		j = time.Duration(float64(interval) * o.jitter_percent / 100)
+	}
+	if interval/2 < j {
+		j = interval / 2
+	}
+	if 0 < j {
+		interval += time.Duration(rand.Int63n(int64(2*j)+1)) - j
+	}
+	return interval
+}
+
+// Go_tick calls f every interval as a Go() task until the Group is canceled.
+// The first call is after interval. A non-nil error from f ends the task and
+// is passed to Set_err(). Use With_jitter() or With_jitter_percent() to keep
+// many processes from calling f at the same time.
+//
+func (o *Group) Go_tick(interval time.Duration, f func(ctx context.Context) error, opt ...tick_option) {
+	t := &ticker{}
+	for _, fn := range opt {
+		fn(t)
+	}
+	o.Go(func() error {
+		if t.jitter <= 0 && t.jitter_percent <= 0 && o.clock == nil {
+			tk := time.NewTicker(interval)
+			defer tk.Stop()
+			for {
+				select {
+				case <-o.Done():
+					return nil
+				case <-tk.C:
+					if err := f(o); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		for sleep(o, t.next(interval)) {
+			if err := f(o); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Go_after calls f once after delay as a Go() task, unless the Group is
+// canceled first.
+//
+func (o *Group) Go_after(delay time.Duration, f func(ctx context.Context) error) {
+	o.Go(func() error {
+		if !sleep(o, delay) {
+			return nil
+		}
+		return f(o)
+	})
+}
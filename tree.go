@@ -0,0 +1,209 @@
+package gogroup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// With_name() names the Group in Dump().
+//
+func With_name(name string) option {
+	return func(o *Group) {
+		o.name = name
+	}
+}
+
+// With_joined_child() makes the parent Group, the Group the context given to
+// With_cancel_nowait(), With_timeout_nowait() or With_values_only() is
+// derived from, Wait() for this Group's tasks too. The child is not a task
+// of the parent: it is not counted by Len(), Tasks(), Stats() or the
+// errgroup and empty wait of the parent. With_cancel(), With_timeout() and
+// With_deadline() children are always joined.
+//
+func With_joined_child() option {
+	return func(o *Group) {
+		o.joined = true
+	}
+}
+
+func (o *Group) join() {
+	if !o.joined && o.parent == nil || o.up == nil {
+		return
+	}
+	up := o.up
+	up.wait_lock.Lock()
+	// Like closed(), but a parent waiting for other children can join one
+	// more.
+	if up.draining || up.Err() != nil && up.wait_register.len() == 0 && up.joins == 0 {
+		up.wait_lock.Unlock()
+		return
+	}
+	up.joins++
+	up.join_wg.Add(1)
+	up.wait_lock.Unlock()
+	var once sync.Once
+	o.unjoin = func() {
+		once.Do(func() {
+			up.wait_lock.Lock()
+			up.joins--
+			idle := up.draining && up.wait_register.len() == 0 && up.joins == 0
+			up.wait_lock.Unlock()
+			up.join_wg.Done()
+			if idle {
+				up.Cancel()
+			}
+		})
+	}
+	go func() {
+		<-o.Done()
+		o.wg().Wait()
+		o.unjoin()
+	}()
+}
+
+func (o *Group) attach(parent *Group) {
+	o.parent = parent
+	o.unlink = context.AfterFunc(parent, o.Cancel)
+}
+
+// Detach makes a With_cancel() or With_timeout() child independent of its
+// parent: parent.Cancel() no longer cancels the child and parent.Wait() no
+// longer waits for the child's tasks. The context values are kept, and so is
+// a parent deadline earlier than the child timeout. Detach does nothing for
+// other Groups or after the parent is canceled.
+//
+func (o *Group) Detach() {
+	o.wait_lock.Lock()
+	parent, unlink := o.parent, o.unlink
+	o.wait_lock.Unlock()
+	if parent == nil || !unlink() {
+		return
+	}
+	o.wait_lock.Lock()
+	o.parent, o.unlink, o.up = nil, nil, nil
+	watch := o.parent_signal
+	o.parent_signal = false
+	o.wait_lock.Unlock()
+	parent.remove_child(o)
+	if o.unjoin != nil {
+		o.unjoin()
+	}
+	if watch {
+		watch_signals(o)
+		if o.Err() != nil {
+			unwatch_signals(o)
+		}
+	}
+}
+
+func (o *Group) add_child(c *Group) {
+	o.wait_lock.Lock()
+	defer o.wait_lock.Unlock()
+	o.children = append(o.children, c)
+}
+
+func (o *Group) remove_child(c *Group) {
+	o.wait_lock.Lock()
+	defer o.wait_lock.Unlock()
+	for i, v := range o.children {
+		if v == c {
+			o.children = append(o.children[:i], o.children[i+1:]...)
+			return
+		}
+	}
+}
+
+// stop_child moves the canceled child c to stopping, where Running() finds
+// it until its tasks end.
+//
+func (o *Group) stop_child(c *Group) {
+	o.remove_child(c)
+	o.wait_lock.Lock()
+	o.stopping = append(o.stopping, c)
+	o.wait_lock.Unlock()
+	o.stopping_children()
+}
+
+// stopping_children returns the canceled children with running tasks and
+// forgets the others.
+//
+func (o *Group) stopping_children() (r []*Group) {
+	o.wait_lock.Lock()
+	stopping := append([]*Group(nil), o.stopping...)
+	o.wait_lock.Unlock()
+	var ended []*Group
+	for _, c := range stopping {
+		c.wait_lock.Lock()
+		running := 0 < c.wait_register.len() || 0 < c.joins || 0 < len(c.stopping)
+		c.wait_lock.Unlock()
+		if running {
+			r = append(r, c)
+		} else {
+			ended = append(ended, c)
+		}
+	}
+	if len(ended) == 0 {
+		return
+	}
+	o.wait_lock.Lock()
+	defer o.wait_lock.Unlock()
+	keep := o.stopping[:0]
+	for _, c := range o.stopping {
+		if !contains(ended, c) {
+			keep = append(keep, c)
+		}
+	}
+	clear(o.stopping[len(keep):])
+	o.stopping = keep
+	return
+}
+
+func contains(groups []*Group, g *Group) bool {
+	for _, v := range groups {
+		if v == g {
+			return true
+		}
+	}
+	return false
+}
+
+// Children returns the Groups made with a context derived from this Group,
+// such as With_cancel() and With_timeout(), that are not canceled.
+//
+func (o *Group) Children() []*Group {
+	o.wait_lock.Lock()
+	defer o.wait_lock.Unlock()
+	return append([]*Group(nil), o.children...)
+}
+
+// Name returns the With_name() name.
+//
+func (o *Group) Name() string {
+	return o.name
+}
+
+// Dump writes the Group and its child Groups as an indented tree: name,
+// state, running task count, deadline, and error. A child is a Group made
+// with a context derived from this Group, until the child is canceled.
+//
+func (o *Group) Dump(w io.Writer) {
+	o.dump(w, 0)
+}
+
+func (o *Group) dump(w io.Writer, depth int) {
+	fmt.Fprintf(w, "%vgroup %q %v tasks=%v", strings.Repeat("  ", depth), o.name, o.State(), o.Len())
+	if d, ok := o.Deadline(); ok {
+		fmt.Fprintf(w, " deadline=%v", d.Format(time.RFC3339))
+	}
+	if err := o.Get_err(); err != nil {
+		fmt.Fprintf(w, " err=%q", err)
+	}
+	fmt.Fprintln(w)
+	for _, c := range o.Children() {
+		c.dump(w, depth+1)
+	}
+}
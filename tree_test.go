@@ -0,0 +1,31 @@
+package gogroup
+
+import (
+	"context"
+	"testing"
+)
+
+func TestDetach_after_cancel(t *testing.T) {
+	for i := 0; i < 2000; i++ {
+		p := New(With_cancel_nowait(context.Background()))
+		c := New(With_cancel(p))
+		p.Cancel()
+		c.Detach()
+		c.Cancel()
+		c.Wait()
+		p.Wait()
+	}
+}
+
+func TestDetach_after_cancel_keeps_parent(t *testing.T) {
+	p := New(With_cancel_nowait(context.Background()))
+	c := New(With_cancel(p))
+	p.Cancel()
+	<-c.Done()
+	c.Detach()
+	if c.up != p {
+		t.Fatal("Detach() after the parent is canceled cleared the parent")
+	}
+	c.Wait()
+	p.Wait()
+}
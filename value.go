@@ -0,0 +1,45 @@
+package gogroup
+
+import "context"
+
+// With_value() adds key and val to the Group Context, like
+// context.WithValue(), so a Group can carry request IDs, loggers or tenancy
+// information from New(). With_value() may be used more than once.
+//
+func With_value(key, val interface{}) option {
+	return func(o *Group) {
+		o.values = append(o.values, [2]interface{}{key, val})
+	}
+}
+
+func (o *Group) with_values() {
+	for _, kv := range o.values {
+		o.Context = context.WithValue(o.Context, kv[0], kv[1])
+	}
+	o.values = nil
+}
+
+// From_context returns the Group ctx is, or is derived from, so code that
+// only receives a ctx can Register() goroutines with the right Group. A Group
+// returns itself for the lookup key, so no injection is needed.
+//
+func From_context(ctx context.Context) (*Group, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	o, ok := ctx.Value(group_key{}).(*Group)
+	return o, ok
+}
+
+// Bind returns a Context derived from ctx that is also canceled when the
+// Group is canceled, and for which From_context() returns the Group. cancel
+// must be called to release it, like context.WithCancel().
+//
+func (o *Group) Bind(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.WithValue(ctx, group_key{}, o))
+	stop := context.AfterFunc(o, cancel)
+	return ctx, func() {
+		stop()
+		cancel()
+	}
+}
@@ -0,0 +1,124 @@
+package gogroup
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"runtime"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// With_shutdown_watchdog() writes the names and goroutine stacks of tasks
+// still registered d after the Group is canceled to w, while Wait() is
+// blocked on them. The dump is written once. The stack of a Register() task
+// is the stack of the goroutine that called Register().
+//
+func With_shutdown_watchdog(d time.Duration, w io.Writer) option {
+	return func(o *Group) {
+		o.goids = true
+		o.graces = append(o.graces, grace{d: d, f: func() {
+			o.dump_tasks(w, fmt.Sprintf("gogroup: shutdown blocked %v after cancel", d))
+		}})
+	}
+}
+
+// dump_tasks writes header, then each registered task with the stack of the
+// goroutine running it.
+//
+func (o *Group) dump_tasks(w io.Writer, header string) {
+	o.wait_lock.Lock()
+	tasks := make([]Task_info, 0, o.wait_register.len())
+	goids := make(map[int]uint64, o.wait_register.len())
+	o.wait_register.each(func(index int, t *task) {
+		tasks = append(tasks, t.info(index))
+		goids[index] = t.goid
+	})
+	o.wait_lock.Unlock()
+	sort.Slice(tasks, func(i, j int) bool { return task_before(tasks[i], tasks[j]) })
+	stacks := goroutine_stacks()
+	fmt.Fprintf(w, "%v: %v task(s) registered\n", header, len(tasks))
+	for _, t := range tasks {
+		fmt.Fprintf(w, "task %v %q running %v\n", t.Index, t.Name, t.Duration().Round(time.Millisecond))
+		if s, ok := stacks[goids[t.Index]]; ok {
+			fmt.Fprintf(w, "%s\n", s)
+		} else {
+			fmt.Fprintf(w, "goroutine %v: stack not found\n\n", goids[t.Index])
+		}
+	}
+}
+
+// Running_task is a task returned by Running().
+//
+type Running_task struct {
+	Task_info
+	Group string // With_name() of the Group of the task
+	Stack []byte // stack of the goroutine running the task; see Task_goroutine
+}
+
+// Running returns the tasks still registered with the Group, and with its
+// child Groups until their tasks end, even after the child is canceled. Use
+// it after Wait() to find tasks that ignore cancelation; see
+// gogrouptest.Verify_shutdown().
+//
+func (o *Group) Running() (r []Running_task) {
+	stacks := goroutine_stacks()
+	o.running(stacks, &r)
+	return
+}
+
+func (o *Group) running(stacks map[uint64][]byte, r *[]Running_task) {
+	o.wait_lock.Lock()
+	start := len(*r)
+	o.wait_register.each(func(index int, t *task) {
+		*r = append(*r, Running_task{Task_info: t.info(index), Group: o.name, Stack: stacks[t.goid]})
+	})
+	children := append([]*Group(nil), o.children...)
+	o.wait_lock.Unlock()
+	tasks := (*r)[start:]
+	sort.Slice(tasks, func(i, j int) bool { return task_before(tasks[i].Task_info, tasks[j].Task_info) })
+	for _, c := range append(children, o.stopping_children()...) {
+		c.running(stacks, r)
+	}
+}
+
+// goroutine_stacks returns the stack of every goroutine by goroutine id.
+//
+func goroutine_stacks() map[uint64][]byte {
+	buf := make([]byte, 1<<20)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+	r := map[uint64][]byte{}
+	for _, s := range bytes.Split(buf, []byte("\n\n")) {
+		if id, ok := parse_goid(s); ok {
+			r[id] = s
+		}
+	}
+	return r
+}
+
+// goid returns the id of the calling goroutine.
+//
+func goid() uint64 {
+	var buf [64]byte
+	id, _ := parse_goid(buf[:runtime.Stack(buf[:], false)])
+	return id
+}
+
+// parse_goid parses "goroutine 18 [running]:".
+//
+func parse_goid(s []byte) (uint64, bool) {
+	s = bytes.TrimPrefix(s, []byte("goroutine "))
+	if i := bytes.IndexByte(s, ' '); 0 < i {
+		id, err := strconv.ParseUint(string(s[:i]), 10, 64)
+		return id, err == nil
+	}
+	return 0, false
+}